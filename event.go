@@ -69,65 +69,61 @@ const (
 	Mouse               = "mouse"     // mouse events
 )
 
-// keep the window that blocks the input.
-var blocker Widget
-var blocked = false
-
-// Block block the application from input.
+// keep the widgets that block the input, the last one is the current
+// blocker.
+var blockers []Widget
+
+// Block blocks the application from input, except for the widget b, and
+// its children. Blocks can be nested (for example, a dialog opened from
+// other dialog), in that case only the last blocker receives the input,
+// until it is unblocked.
 func Block(b Widget) {
-	if blocked {
-		return
+	for _, w := range blockers {
+		if w == b {
+			return
+		}
 	}
-	blocked = true
-	blocker = b
+	blockers = append(blockers, b)
 }
 
 // IsBlock returns true if the application is blocked from input.
 func IsBlock() bool {
-	return blocked
+	return len(blockers) > 0
 }
 
 // IsBlocker returns true if the widget (or its parent) is blocking the
 // input.
 func IsBlocker(w Widget) bool {
-	if w == blocker {
-		return true
+	if len(blockers) == 0 {
+		return false
 	}
-	for w != nil {
-		p := w.Property(Parent)
-		if p == nil {
-			break
-		}
-		w = p.(Widget)
-		if w == blocker {
-			return true
-		}
-	}
-	return false
+	return isChild(w, blockers[len(blockers)-1])
 }
 
-// Unblock opens the application for user input. The widget requesting
+// Unblock removes a block of the application. The widget requesting
 // the unblocking must be the same or a child of the widget that request
-// the blocking.
+// the blocking. If there are no more blocks, the application is open for
+// user input.
 func Unblock(req Widget) {
-	if !blocked {
-		return
-	}
-	if req == blocker {
-		blocked = false
-		blocker = nil
-		return
+	for i := len(blockers) - 1; i >= 0; i-- {
+		if isChild(req, blockers[i]) {
+			blockers = append(blockers[:i], blockers[i+1:]...)
+			return
+		}
 	}
-	for req != nil {
-		p := req.Property(Parent)
-		if p == nil {
-			break
+}
+
+// IsChild returns true if a widget is p, or a child of p.
+func isChild(w, p Widget) bool {
+	for w != nil {
+		if w == p {
+			return true
 		}
-		req = p.(Widget)
-		if req == blocker {
-			blocked = false
-			blocker = nil
-			return
+		v := w.Property(Parent)
+		if v == nil {
+			break
 		}
+		w = v.(Widget)
 	}
+	return false
 }
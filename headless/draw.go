@@ -0,0 +1,257 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package headless
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+
+	"github.com/js-arias/sparta"
+)
+
+// Draw sets the drawing mode of the window.
+func (win *window) Draw(mode bool) {
+	if win.isExpose {
+		return
+	}
+	if mode {
+		win.resetFore()
+	}
+}
+
+// ResetFore sets the fore color of the window as the drawing color. The
+// color is grayed if the window is disabled.
+func (win *window) resetFore() {
+	win.fg = win.fore
+	if !win.isEnabled() {
+		win.fg = grayed(win.fore)
+	}
+}
+
+// Text draws text in the window.
+func (win *window) Text(pt image.Point, text string) {
+	if len(text) == 0 {
+		return
+	}
+	if r := []rune(text); len(r) > 128 {
+		text = string(r[:128])
+	}
+	x := pt.X
+	for _, r := range text {
+		win.fill(image.Rect(x, pt.Y-2, x+sparta.WidthUnit, pt.Y+11), win.bg)
+		g, ok := glyphs[r]
+		if !ok {
+			// unknown glyphs are drawn as a box
+			win.Rectangle(image.Rect(x, pt.Y+2, x+4, pt.Y+8), false)
+			x += sparta.WidthUnit
+			continue
+		}
+		for i, col := range g {
+			for j := uint(0); j < 8; j++ {
+				if col&(1<<j) != 0 {
+					win.img.SetRGBA(x+i, pt.Y+2+int(j), win.fg)
+				}
+			}
+		}
+		x += sparta.WidthUnit
+	}
+}
+
+// Rectangle draws a rectangle in the window.
+func (win *window) Rectangle(rect image.Rectangle, fill bool) {
+	if fill {
+		win.fill(rect, win.fg)
+	}
+	win.Lines([]image.Point{
+		rect.Min,
+		image.Pt(rect.Max.X, rect.Min.Y),
+		rect.Max,
+		image.Pt(rect.Min.X, rect.Max.Y),
+		rect.Min,
+	})
+}
+
+// Lines draws one or more lines in the window.
+func (win *window) Lines(pt []image.Point) {
+	if len(pt) < 2 {
+		return
+	}
+	for i := 1; i < len(pt); i++ {
+		win.line(pt[i-1], pt[i])
+	}
+}
+
+// Arc draws an arc on the window.
+func (win *window) Arc(rect image.Rectangle, angle1, angle2 float64, fill bool) {
+	rx, ry := float64(rect.Dx())/2, float64(rect.Dy())/2
+	cx, cy := float64(rect.Min.X)+rx, float64(rect.Min.Y)+ry
+	if !fill {
+		steps := int(math.Abs(angle2)*math.Max(rx, ry)) + 1
+		pts := make([]image.Point, steps+1)
+		for i := range pts {
+			a := angle1 + (angle2 * float64(i) / float64(steps))
+			pts[i] = image.Pt(int(math.Floor(cx+rx*math.Cos(a)+0.5)), int(math.Floor(cy-ry*math.Sin(a)+0.5)))
+		}
+		win.Lines(pts)
+		return
+	}
+	if rx == 0 || ry == 0 {
+		return
+	}
+	start, end := angle1, angle1+angle2
+	if end < start {
+		start, end = end, start
+	}
+	full := (end - start) >= 2*math.Pi
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		dy := (cy - (float64(y) + 0.5)) / ry
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			dx := ((float64(x) + 0.5) - cx) / rx
+			if (dx*dx)+(dy*dy) > 1 {
+				continue
+			}
+			if !full && !inAngle(math.Atan2(dy, dx), start, end) {
+				continue
+			}
+			win.img.SetRGBA(x, y, win.fg)
+		}
+	}
+}
+
+// InAngle returns true if an angle is between start and end.
+func inAngle(a, start, end float64) bool {
+	for a < start {
+		a += 2 * math.Pi
+	}
+	for a-2*math.Pi >= start {
+		a -= 2 * math.Pi
+	}
+	return a <= end
+}
+
+// Polygon draws a polygon on the window.
+func (win *window) Polygon(pt []image.Point, fill bool) {
+	if len(pt) < 2 {
+		return
+	}
+	if !fill {
+		if !pt[0].Eq(pt[len(pt)-1]) {
+			pt = append(append([]image.Point{}, pt...), pt[0])
+		}
+		win.Lines(pt)
+		return
+	}
+
+	// even-odd scan line filling
+	bounds := image.Rectangle{pt[0], pt[0]}
+	for _, p := range pt {
+		bounds = bounds.Union(image.Rectangle{p, p.Add(image.Pt(1, 1))})
+	}
+	var xs []float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		sy := float64(y) + 0.5
+		xs = xs[:0]
+		for i := range pt {
+			a, b := pt[i], pt[(i+1)%len(pt)]
+			if a.Y == b.Y {
+				continue
+			}
+			ay, by := float64(a.Y), float64(b.Y)
+			if (sy < math.Min(ay, by)) || (sy >= math.Max(ay, by)) {
+				continue
+			}
+			xs = append(xs, float64(a.X)+(sy-ay)*float64(b.X-a.X)/(by-ay))
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0 := int(math.Ceil(xs[i] - 0.5))
+			x1 := int(math.Ceil(xs[i+1] - 0.5))
+			for x := x0; x < x1; x++ {
+				win.img.SetRGBA(x, y, win.fg)
+			}
+		}
+	}
+}
+
+// Pixel draws a pixel on the window.
+func (win *window) Pixel(pt image.Point) {
+	win.img.SetRGBA(pt.X, pt.Y, win.fg)
+}
+
+// SetColor sets the fore color of the window.
+func (win *window) SetColor(p sparta.Property, c color.RGBA) {
+	if (p != sparta.Background) && (p != sparta.Foreground) {
+		return
+	}
+	if p == sparta.Foreground {
+		win.fg = opaque(c)
+		if !win.isEnabled() {
+			win.fg = grayed(win.fg)
+		}
+	} else {
+		win.bg = opaque(c)
+	}
+}
+
+// Grayed returns the grayed version of a color, used to draw the
+// foreground of disabled windows.
+func grayed(c color.RGBA) color.RGBA {
+	return color.RGBA{
+		R: c.R/3 + 128,
+		G: c.G/3 + 128,
+		B: c.B/3 + 128,
+		A: c.A,
+	}
+}
+
+// Opaque returns a color without transparency, as colors in sparta
+// ignore the alpha channel.
+func opaque(c color.RGBA) color.RGBA {
+	c.A = 255
+	return c
+}
+
+// Fill fills a rectangle of the window with a color.
+func (win *window) fill(rect image.Rectangle, c color.RGBA) {
+	draw.Draw(win.img, rect, image.NewUniform(c), image.ZP, draw.Src)
+}
+
+// Line draws a line using the Bresenham algorithm.
+func (win *window) line(p0, p1 image.Point) {
+	dx, dy := abs(p1.X-p0.X), -abs(p1.Y-p0.Y)
+	sx, sy := 1, 1
+	if p0.X > p1.X {
+		sx = -1
+	}
+	if p0.Y > p1.Y {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		win.img.SetRGBA(p0.X, p0.Y, win.fg)
+		if p0.Eq(p1) {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			p0.X += sx
+		}
+		if e2 <= dx {
+			e += dx
+			p0.Y += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
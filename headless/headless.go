@@ -0,0 +1,162 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+// Package headless defines an in-memory backend for sparta.
+//
+// Instead of connecting to a display, each window is rasterized into an
+// image.RGBA, so widgets can be used in machines without a graphic
+// server (for example, when running tests). To use it, import this
+// package instead of the init package:
+//
+//	import _ "github.com/js-arias/sparta/headless"
+//
+// Events are processed in the same way as in the x11 backend: a window
+// is exposed when created, after a geometry change, or when updated, and
+// command events are queued, and then delivered by the event loop.
+package headless
+
+import (
+	"image"
+	"image/draw"
+	"sync"
+
+	"github.com/js-arias/sparta"
+)
+
+func init() {
+	sparta.WidthUnit = 6
+	sparta.HeightUnit = 13 + 2
+}
+
+// event is a pending event of a window.
+type event struct {
+	win *window
+	ev  interface{}
+}
+
+var (
+	queueMu sync.Mutex
+	queue   []event
+
+	// wake is signaled each time an event is queued, or when the
+	// application is closed.
+	wake = make(chan struct{}, 1)
+	done bool
+)
+
+// Post adds an event to the event queue.
+func post(win *window, ev interface{}) {
+	queueMu.Lock()
+	defer func() {
+		queueMu.Unlock()
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}()
+	// only one expose event is processed per window, so pending
+	// expose events are merged.
+	if ex, ok := ev.(sparta.ExposeEvent); ok {
+		for i, e := range queue {
+			if e.win != win {
+				continue
+			}
+			if pe, ok := e.ev.(sparta.ExposeEvent); ok {
+				queue[i].ev = sparta.ExposeEvent{Rect: pe.Rect.Union(ex.Rect)}
+				return
+			}
+		}
+	}
+	queue = append(queue, event{win: win, ev: ev})
+}
+
+// Next returns the next event in the queue.
+func next() (event, bool) {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+	if len(queue) == 0 {
+		return event{}, false
+	}
+	e := queue[0]
+	queue[0] = event{}
+	queue = queue[1:]
+	return e, true
+}
+
+// Post sends an event to the window of a widget, as if it was produced
+// by the user. Accepted events are MouseEvent, KeyEvent, ConfigureEvent
+// (that changes the geometry of the window), and CloseEvent (that is only
+// sent to windows without a parent). The event will be processed in the
+// next call to Flush, or by the event loop.
+func Post(w sparta.Widget, ev interface{}) {
+	win, ok := w.Window().(*window)
+	if !ok {
+		return
+	}
+	switch ev.(type) {
+	case sparta.MouseEvent, sparta.KeyEvent, sparta.ConfigureEvent, sparta.CloseEvent:
+		post(win, ev)
+	}
+}
+
+// Trace, if set, is called with each event before it is sent to a widget.
+var Trace func(sparta.Widget, interface{})
+
+// Flush processes all the pending events, including the events produced
+// while processing them, and returns when the event queue is empty. It is
+// useful to run the application step by step, without calling Run.
+func Flush() {
+	for {
+		e, ok := next()
+		if !ok {
+			return
+		}
+		procEvent(e)
+	}
+}
+
+// Windows returns the widgets with a top level window (i.e. main windows
+// and popups), in creation order.
+func Windows() []sparta.Widget {
+	ws := make([]sparta.Widget, 0, len(tops))
+	for _, win := range tops {
+		ws = append(ws, win.w)
+	}
+	return ws
+}
+
+// Image returns a copy of the content of the window of a widget. The
+// content of the children windows is not included. If the widget does not
+// have a headless window it returns nil.
+func Image(w sparta.Widget) *image.RGBA {
+	win, ok := w.Window().(*window)
+	if !ok {
+		return nil
+	}
+	img := image.NewRGBA(win.img.Bounds())
+	copy(img.Pix, win.img.Pix)
+	return img
+}
+
+// Snapshot returns an image with the content of the window of a widget,
+// including the content of its visible children windows. If the widget does not
+// have a headless window it returns nil.
+func Snapshot(w sparta.Widget) *image.RGBA {
+	img := Image(w)
+	if img == nil {
+		return nil
+	}
+	vc := w.Property(sparta.Childs)
+	if vc == nil {
+		return img
+	}
+	for _, c := range vc.([]sparta.Widget) {
+		cWin, ok := c.Window().(*window)
+		if !ok || cWin.hidden {
+			continue
+		}
+		draw.Draw(img, cWin.rect, Snapshot(c), image.ZP, draw.Src)
+	}
+	return img
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package headless
+
+import (
+	"image"
+
+	"github.com/js-arias/sparta"
+)
+
+func init() {
+	sparta.Run = run
+	sparta.Close = closeApp
+	sparta.SendEvent = sendEvent
+	sparta.Grab = grabPointer
+	sparta.Ungrab = ungrab
+}
+
+// grab is the widget that grabs the pointer.
+var grab sparta.Widget
+
+// GrabPointer grabs the pointer for a widget.
+func grabPointer(w sparta.Widget) {
+	grab = w
+}
+
+// Ungrab releases the pointer.
+func ungrab() {
+	grab = nil
+}
+
+// InGrab returns true if the widget is the grabbing widget, or one of its
+// descendants.
+func inGrab(w sparta.Widget) bool {
+	for w != nil {
+		if w == grab {
+			return true
+		}
+		p := w.Property(sparta.Parent)
+		if p == nil {
+			break
+		}
+		w = p.(sparta.Widget)
+	}
+	return false
+}
+
+// SendEvent sends an event to the window.
+func sendEvent(dest sparta.Widget, comm sparta.CommandEvent) {
+	dwin, ok := dest.Window().(*window)
+	if !ok {
+		return
+	}
+	post(dwin, comm)
+}
+
+// Run runs the headless event loop.
+func run() {
+	for {
+		Flush()
+		queueMu.Lock()
+		if done {
+			done = false
+			queueMu.Unlock()
+			return
+		}
+		queueMu.Unlock()
+		<-wake
+	}
+}
+
+// CloseApp closes the application.
+func closeApp() {
+	queueMu.Lock()
+	done = true
+	queueMu.Unlock()
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// ProcEvent process a headless event.
+func procEvent(e event) {
+	w, ok := widgetTable[e.win]
+	if !ok {
+		return
+	}
+	win := e.win
+	switch ev := e.ev.(type) {
+	case sparta.CloseEvent:
+		if (w.Property(sparta.Parent) != nil) || isBlocked(w) {
+			break
+		}
+		deliver(w, ev)
+	case sparta.CommandEvent:
+		if ev.Source != nil && ev.Source.Window() == nil {
+			ev.Source = nil
+		}
+		deliver(w, ev)
+	case sparta.ConfigureEvent:
+		win.resize(ev.Rect)
+		rect := w.Property(sparta.Geometry).(image.Rectangle)
+		if rect.Eq(ev.Rect) {
+			break
+		}
+		deliver(w, ev)
+		if (rect.Dx() != ev.Rect.Dx()) || (rect.Dy() != ev.Rect.Dy()) {
+			post(win, sparta.ExposeEvent{Rect: win.img.Bounds()})
+		}
+	case sparta.ExposeEvent:
+		win.fill(ev.Rect, win.back)
+		win.bg = win.back
+		win.resetFore()
+		win.isExpose = true
+		deliver(w, ev)
+		win.isExpose = false
+	case sparta.KeyEvent:
+		if !win.isVisible() || !win.isEnabled() || isBlocked(w) {
+			break
+		}
+		deliver(w, ev)
+	case sparta.MouseEvent:
+		if !win.isVisible() || !win.isEnabled() {
+			break
+		}
+		if (grab != nil) && !inGrab(w) {
+			gWin := grab.Window().(*window)
+			ev.Loc = ev.Loc.Add(win.rootPos()).Sub(gWin.rootPos())
+			w = grab
+		}
+		if isBlocked(w) {
+			break
+		}
+		// the focus is set before the event is delivered, so the
+		// widget can move the focus to other widget (e.g. a popup).
+		if (ev.Button > 0) || (ev.Button == -sparta.MouseWheel) {
+			w.Focus()
+		}
+		deliver(w, ev)
+	}
+}
+
+// IsBlocked returns true if the input of a widget is blocked by other
+// widget (for example, a modal dialog).
+func isBlocked(w sparta.Widget) bool {
+	return sparta.IsBlock() && !sparta.IsBlocker(w)
+}
+
+// Deliver sends an event to a widget.
+func deliver(w sparta.Widget, ev interface{}) {
+	if Trace != nil {
+		Trace(w, ev)
+	}
+	w.OnEvent(ev)
+}
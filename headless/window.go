@@ -0,0 +1,227 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package headless
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/js-arias/sparta"
+)
+
+// widgetTable holds a list of widgets.
+var widgetTable = make(map[*window]sparta.Widget)
+
+// tops holds the top level windows, in creation order.
+var tops []*window
+
+// focus is the window with the input focus.
+var focus *window
+
+// Window holds the window information.
+type window struct {
+	w      sparta.Widget // associated widget
+	parent *window
+	rect   image.Rectangle // position relative to the parent
+
+	// state
+	hidden   bool // true if the window is not mapped
+	disabled bool // true if the window does not receive input
+
+	// graphic part
+	img        *image.RGBA
+	isExpose   bool // true if the window is processing an expose event.
+	back, fore color.RGBA
+	fg, bg     color.RGBA // current drawing colors
+}
+
+func init() {
+	sparta.NewWindow = newWindow
+	sparta.RootPos = rootPos
+	sparta.Focused = focused
+}
+
+// NewWindow creates a new window and assigns it to a widget.
+func newWindow(w sparta.Widget) {
+	r := w.Property(sparta.Geometry).(image.Rectangle)
+	win := &window{
+		w:    w,
+		rect: r,
+		img:  image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy())),
+		back: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		fore: color.RGBA{A: 255},
+	}
+	popup := false
+	if v, ok := w.Property(sparta.Popup).(bool); ok {
+		popup = v
+	}
+	if p := w.Property(sparta.Parent); (p != nil) && !popup {
+		pw := p.(sparta.Widget)
+		pWin := pw.Window().(*window)
+		win.back = pWin.back
+		win.fore = pWin.fore
+		win.parent = pWin
+		pw.SetProperty(sparta.Childs, w)
+	} else {
+		tops = append(tops, win)
+	}
+	win.fg, win.bg = win.fore, win.back
+	widgetTable[win] = w
+	w.SetWindow(win)
+	win.fill(win.img.Bounds(), win.back)
+
+	// a mapped window is always exposed
+	post(win, sparta.ExposeEvent{Rect: win.img.Bounds()})
+}
+
+// Close closes the window.
+func (win *window) Close() {
+	// close the childs
+	vc := win.w.Property(sparta.Childs)
+	if vc != nil {
+		for _, c := range vc.([]sparta.Widget) {
+			// a child can be closed before its parent
+			if cw := c.Window(); cw != nil {
+				cw.Close()
+			}
+		}
+	}
+	delete(widgetTable, win)
+	if focus == win {
+		focus = nil
+	}
+	if grab == win.w {
+		grab = nil
+	}
+	for i, t := range tops {
+		if t == win {
+			tops = append(tops[:i], tops[i+1:]...)
+			break
+		}
+	}
+
+	if win.w.Property(sparta.Parent) != nil {
+		win.w.SetProperty(sparta.Parent, nil)
+	}
+	win.w.SetProperty(sparta.Childs, nil)
+	win.w.RemoveWindow()
+	win.w = nil
+
+	// if there are no more windows, close the app
+	if len(widgetTable) == 0 {
+		closeApp()
+	}
+}
+
+// SetProperty sets a window property.
+func (win *window) SetProperty(p sparta.Property, v interface{}) {
+	switch p {
+	case sparta.Geometry:
+		post(win, sparta.ConfigureEvent{Rect: v.(image.Rectangle)})
+	case sparta.Visible:
+		hidden := !v.(bool)
+		if win.hidden == hidden {
+			break
+		}
+		win.hidden = hidden
+		if !hidden {
+			// as in other backends, a mapped window is exposed
+			post(win, sparta.ExposeEvent{Rect: win.img.Bounds()})
+		}
+	case sparta.Enabled:
+		disabled := !v.(bool)
+		if win.disabled == disabled {
+			break
+		}
+		win.disabled = disabled
+		updateTree(win.w)
+	case sparta.Foreground:
+		win.fore = opaque(v.(color.RGBA))
+	case sparta.Background:
+		win.back = opaque(v.(color.RGBA))
+	}
+}
+
+// Update updates the window.
+func (win *window) Update() {
+	post(win, sparta.ExposeEvent{Rect: win.img.Bounds()})
+}
+
+// Focus set the focus on the window.
+func (win *window) Focus() {
+	focus = win
+}
+
+// Focused returns the widget with the input focus.
+func focused() sparta.Widget {
+	if focus == nil {
+		return nil
+	}
+	return focus.w
+}
+
+// IsVisible returns true if the window, and all of its ancestors, are
+// visible.
+func (win *window) isVisible() bool {
+	for ; win != nil; win = win.parent {
+		if win.hidden {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEnabled returns true if the window, and all of its ancestors, are
+// enabled.
+func (win *window) isEnabled() bool {
+	for ; win != nil; win = win.parent {
+		if win.disabled {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateTree updates the window of a widget, and the windows of its
+// children.
+func updateTree(w sparta.Widget) {
+	win, ok := w.Window().(*window)
+	if !ok {
+		return
+	}
+	win.Update()
+	if vc := w.Property(sparta.Childs); vc != nil {
+		for _, c := range vc.([]sparta.Widget) {
+			updateTree(c)
+		}
+	}
+}
+
+// RootPos returns the position of the window in screen coordinates.
+func (win *window) rootPos() image.Point {
+	pt := win.rect.Min
+	for p := win.parent; p != nil; p = p.parent {
+		pt = pt.Add(p.rect.Min)
+	}
+	return pt
+}
+
+// RootPos returns the position of a widget in screen coordinates.
+func rootPos(w sparta.Widget) image.Point {
+	return w.Window().(*window).rootPos()
+}
+
+// Resize changes the size of the window.
+func (win *window) resize(r image.Rectangle) {
+	win.rect = r
+	if win.img.Bounds().Dx() == r.Dx() && win.img.Bounds().Dy() == r.Dy() {
+		return
+	}
+	img := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(img, img.Bounds(), image.NewUniform(win.back), image.ZP, draw.Src)
+	draw.Draw(img, img.Bounds(), win.img, image.ZP, draw.Src)
+	win.img = img
+}
@@ -23,11 +23,12 @@ const (
 	StateShift   StateKey = 1
 	StateLock             = 2
 	StateCtrl             = 4
+	StateAlt              = 8
 	StateAltGr            = 128
 	StateButtonL          = 256
 	StateButton2          = 512
 	StateButtonR          = 1024
-	StateAny              = 7 | StateAltGr | StateButtonL | StateButton2 | StateButtonR
+	StateAny              = 15 | StateAltGr | StateButtonL | StateButton2 | StateButtonR
 )
 
 // Key is a keyboard key.
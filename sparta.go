@@ -53,6 +53,33 @@ const (
 	// efect in the next expose event of the widget.
 	Border = "border"
 
+	// Owner widget (Widget) of a top level window. The window is kept
+	// over its owner (for example, a dialog over a main window). This
+	// property is read by the backend when the window is created.
+	Owner = "owner"
+
+	// Popup indicates that the widget is shown in a popup window (bool):
+	// a top-level window without decorations, that is shown over
+	// other windows. The geometry of a popup is in screen coordinates.
+	// This property is read by the backend when the window is created.
+	Popup = "popup"
+
+	// PrefSize is the preferred size of the widget (image.Point), used
+	// by the layout containers. It is read only. If a widget does not
+	// have a preferred size, its current size is used.
+	PrefSize = "prefsize"
+
+	// Visible shows or hides a widget (bool). A hidden widget, and its
+	// children, is not drawn and does not receive mouse or keyboard
+	// events. By default the widgets are visible.
+	Visible = "visible"
+
+	// Enabled enables or disables the input of a widget (bool). A
+	// disabled widget, and its children, does not receive mouse or
+	// keyboard events, and its foreground is drawn with a grayed color.
+	// By default the widgets are enabled.
+	Enabled = "enabled"
+
 	// Target widget (Widget), used in widgets that sends events to
 	// another widget (such a button). If the target is set to nil, then
 	// the widget will send the events to its parent.
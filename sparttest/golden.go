@@ -0,0 +1,158 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package sparttest
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/js-arias/sparta/headless"
+)
+
+var update = flag.Bool("update", false, "update the golden image files")
+
+// Golden compares the content of a widget, including its children, with
+// a golden PNG image file (usually stored in the testdata directory).
+// Tolerance is the maximum difference allowed in each color channel of a
+// pixel.
+//
+// If the test is run with the -update flag, the golden file is
+// written with the current content of the widget.
+func (t *Tester) Golden(name, file string, tolerance uint8) {
+	t.tb.Helper()
+	img := headless.Snapshot(t.Widget(name))
+	if img == nil {
+		t.tb.Fatalf("sparttest: widget %q has no headless window", name)
+	}
+	CheckGolden(t.tb, img, file, tolerance)
+}
+
+// CheckGolden compares an image with a golden PNG image file. Tolerance
+// is the maximum difference allowed in each color channel of a pixel.
+//
+// On mismatch, the test fails, and two files are written besides the
+// golden file: one with the image (ended in ".got.png"), and other with
+// the differences (ended in ".diff.png"), in which the mismatched pixels
+// are shown in red.
+//
+// If the test is run with the -update flag, the golden file is
+// written with the image.
+func CheckGolden(tb testing.TB, img image.Image, file string, tolerance uint8) {
+	tb.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			tb.Fatalf("sparttest: %v", err)
+		}
+		if err := writePNG(file, img); err != nil {
+			tb.Fatalf("sparttest: %v", err)
+		}
+		return
+	}
+	want, err := readPNG(file)
+	if err != nil {
+		tb.Fatalf("sparttest: %v (run with -update to create it)", err)
+	}
+	diff, n := Diff(img, want, tolerance)
+	if n == 0 {
+		return
+	}
+	base := strings.TrimSuffix(file, ".png")
+	if err := writePNG(base+".got.png", img); err != nil {
+		tb.Errorf("sparttest: %v", err)
+	}
+	if err := writePNG(base+".diff.png", diff); err != nil {
+		tb.Errorf("sparttest: %v", err)
+	}
+	tb.Errorf("sparttest: %s: %d pixels are different", file, n)
+}
+
+// Diff compares two images, and returns an image with the differences,
+// and the number of different pixels. Tolerance is the maximum difference
+// allowed in each color channel of a pixel. If the images have different
+// sizes, the area outside of one of the images is counted as different.
+//
+// In the differences image, the matched pixels are shown in a faded
+// gray, and the mismatched pixels in red.
+func Diff(got, want image.Image, tolerance uint8) (*image.RGBA, int) {
+	gb, wb := got.Bounds(), want.Bounds()
+	w, h := gb.Dx(), gb.Dy()
+	if wb.Dx() > w {
+		w = wb.Dx()
+	}
+	if wb.Dy() > h {
+		h = wb.Dy()
+	}
+	diff := image.NewRGBA(image.Rect(0, 0, w, h))
+	n := 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			gp, wp := image.Pt(x, y).Add(gb.Min), image.Pt(x, y).Add(wb.Min)
+			if !gp.In(gb) || !wp.In(wb) {
+				diff.SetRGBA(x, y, color.RGBA{R: 255, A: 255})
+				n++
+				continue
+			}
+			gc := color.RGBAModel.Convert(got.At(gp.X, gp.Y)).(color.RGBA)
+			wc := color.RGBAModel.Convert(want.At(wp.X, wp.Y)).(color.RGBA)
+			if !similar(gc, wc, tolerance) {
+				diff.SetRGBA(x, y, color.RGBA{R: 255, A: 255})
+				n++
+				continue
+			}
+			g := uint8(191 + (int(gc.R)+int(gc.G)+int(gc.B))/12)
+			diff.SetRGBA(x, y, color.RGBA{R: g, G: g, B: g, A: 255})
+		}
+	}
+	return diff, n
+}
+
+// Similar returns true if the difference of each channel of two colors is
+// smaller or equal to the tolerance.
+func similar(a, b color.RGBA, tolerance uint8) bool {
+	if absDiff(a.R, b.R) > tolerance {
+		return false
+	}
+	if absDiff(a.G, b.G) > tolerance {
+		return false
+	}
+	if absDiff(a.B, b.B) > tolerance {
+		return false
+	}
+	return absDiff(a.A, b.A) <= tolerance
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func readPNG(file string) (image.Image, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(file string, img image.Image) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+// Package sparttest provides utilities to test sparta widgets.
+//
+// It uses the headless backend, so tests can be run without a display.
+// A Tester is created with the root widget of the tree to be tested, then
+// events are sent to the widgets of the tree (identified by its name),
+// and the command events produced, and the content of the widgets can be
+// checked, either directly, or by comparing them with golden image
+// files.
+//
+// As the backend state is global, tests that use a Tester should not be
+// run in parallel.
+//
+// The package defines the -update test flag (see Golden), so the test
+// packages that use it should not define a flag with the same name.
+package sparttest
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/headless"
+)
+
+// Command is a command event received by a widget.
+type Command struct {
+	Dest sparta.Widget // widget that receive the command
+	sparta.CommandEvent
+}
+
+// Tester sends events to a widget tree and records its results.
+type Tester struct {
+	tb   testing.TB
+	root sparta.Widget
+	comm []Command
+}
+
+// New creates a new tester for the widget tree of the given root. All
+// the pending events of the tree are processed, so the widgets are ready
+// to receive events.
+func New(tb testing.TB, root sparta.Widget) *Tester {
+	t := &Tester{
+		tb:   tb,
+		root: root,
+	}
+	headless.Trace = t.trace
+	tb.Cleanup(func() {
+		headless.Trace = nil
+	})
+	t.Idle()
+	return t
+}
+
+// Trace records the command events.
+func (t *Tester) trace(w sparta.Widget, e interface{}) {
+	if ev, ok := e.(sparta.CommandEvent); ok {
+		t.comm = append(t.comm, Command{Dest: w, CommandEvent: ev})
+	}
+}
+
+// Idle runs the event loop until there are no more pending events.
+func (t *Tester) Idle() {
+	headless.Flush()
+}
+
+// Widget returns the widget with the given name. The widget is searched
+// in the tree of the root, and then in the tree of the other top level
+// windows (for example, popups). The test fails if there is no widget with
+// that name.
+func (t *Tester) Widget(name string) sparta.Widget {
+	t.tb.Helper()
+	if w := Find(t.root, name); w != nil {
+		return w
+	}
+	for _, tw := range headless.Windows() {
+		if w := Find(tw, name); w != nil {
+			return w
+		}
+	}
+	t.tb.Fatalf("sparttest: widget %q not found", name)
+	return nil
+}
+
+// Find returns the widget with the given name in the widget tree of the
+// root. It returns nil if the widget is not found.
+func Find(root sparta.Widget, name string) sparta.Widget {
+	if nm, ok := root.Property(sparta.Name).(string); ok && nm == name {
+		return root
+	}
+	vc := root.Property(sparta.Childs)
+	if vc == nil {
+		return nil
+	}
+	for _, c := range vc.([]sparta.Widget) {
+		if w := Find(c, name); w != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+// Mouse sends a mouse event to a widget, and runs the event loop until
+// idle.
+func (t *Tester) Mouse(name string, ev sparta.MouseEvent) {
+	t.tb.Helper()
+	headless.Post(t.Widget(name), ev)
+	t.Idle()
+}
+
+// Click sends a mouse button press, and release, to the given point of
+// a widget. If the widget is closed by the press, the release is
+// discarded.
+func (t *Tester) Click(name string, button sparta.MouseButton, pt image.Point) {
+	t.tb.Helper()
+	w := t.Widget(name)
+	headless.Post(w, sparta.MouseEvent{Button: button, Loc: pt})
+	t.Idle()
+	headless.Post(w, sparta.MouseEvent{Button: -button, Loc: pt})
+	t.Idle()
+}
+
+// Key sends a key event to a widget, and runs the event loop until idle.
+func (t *Tester) Key(name string, ev sparta.KeyEvent) {
+	t.tb.Helper()
+	headless.Post(t.Widget(name), ev)
+	t.Idle()
+}
+
+// Type sends the press, and release, of each key to a widget. If the
+// widget is closed by a key, the remaining keys are discarded.
+func (t *Tester) Type(name string, keys ...sparta.Key) {
+	t.tb.Helper()
+	w := t.Widget(name)
+	for _, k := range keys {
+		headless.Post(w, sparta.KeyEvent{Key: k})
+		t.Idle()
+		headless.Post(w, sparta.KeyEvent{Key: -k})
+		t.Idle()
+	}
+}
+
+// TypeString sends the press, and release, of each rune of a string to a
+// widget.
+func (t *Tester) TypeString(name, s string) {
+	t.tb.Helper()
+	for _, r := range s {
+		t.Type(name, sparta.Key(r))
+	}
+}
+
+// Configure changes the geometry of a widget, as if it was done by the
+// user, and runs the event loop until idle.
+func (t *Tester) Configure(name string, rect image.Rectangle) {
+	t.tb.Helper()
+	headless.Post(t.Widget(name), sparta.ConfigureEvent{Rect: rect})
+	t.Idle()
+}
+
+// Close sends a close request to a widget, as if it was done by the user,
+// and runs the event loop until idle. As in other backends, only widgets
+// without a parent receive the close event.
+func (t *Tester) Close(name string) {
+	t.tb.Helper()
+	headless.Post(t.Widget(name), sparta.CloseEvent{})
+	t.Idle()
+}
+
+// Commands returns the command events received since the last call to
+// Commands.
+func (t *Tester) Commands() []Command {
+	comm := t.comm
+	t.comm = nil
+	return comm
+}
+
+// ExpectCommand checks that the widget dest received a command event from
+// the widget src, with the given value, since the last call to Commands.
+// If src is empty, any source is accepted.
+func (t *Tester) ExpectCommand(dest, src string, value int) {
+	t.tb.Helper()
+	d := t.Widget(dest)
+	for _, c := range t.comm {
+		if c.Dest != d || c.Value != value {
+			continue
+		}
+		if src != "" {
+			if c.Source == nil {
+				continue
+			}
+			if nm, _ := c.Source.Property(sparta.Name).(string); nm != src {
+				continue
+			}
+		}
+		return
+	}
+	t.tb.Errorf("sparttest: command %d from %q to %q not found", value, src, dest)
+}
+
+// Image returns the content of a widget. The content of its children is
+// not included.
+func (t *Tester) Image(name string) *image.RGBA {
+	t.tb.Helper()
+	return headless.Image(t.Widget(name))
+}
+
+// Pixel returns the color of a pixel of a widget.
+func (t *Tester) Pixel(name string, pt image.Point) color.RGBA {
+	t.tb.Helper()
+	img := t.Image(name)
+	if img == nil {
+		t.tb.Fatalf("sparttest: widget %q has no headless window", name)
+	}
+	return img.RGBAAt(pt.X, pt.Y)
+}
+
+// ExpectPixel checks that a pixel of a widget has the given color. As
+// in sparta colors are always opaque, the alpha value is ignored.
+func (t *Tester) ExpectPixel(name string, pt image.Point, c color.RGBA) {
+	t.tb.Helper()
+	got := t.Pixel(name, pt)
+	c.A = got.A
+	if got != c {
+		t.tb.Errorf("sparttest: pixel %v of %q: got %v, want %v", pt, name, got, c)
+	}
+}
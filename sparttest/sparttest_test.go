@@ -0,0 +1,222 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package sparttest_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+// Recorder is a testing.TB that records the errors, instead of failing
+// the test.
+type recorder struct {
+	testing.TB
+	errs []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+var (
+	white = color.RGBA{R: 255, G: 255, B: 255}
+	black = color.RGBA{}
+)
+
+// NewCanvas returns a main window with a canvas, in which a filled black
+// rectangle is drawn.
+func newCanvas(name string) *widget.MainWindow {
+	m := widget.NewMainWindow(name, "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 100, 100))
+	c := widget.NewCanvas(m, name+"Canvas", image.Rect(10, 10, 60, 60))
+	c.Capture(sparta.Expose, func(w sparta.Widget, e interface{}) bool {
+		w.(*widget.Canvas).Draw(widget.Rectangle{Rect: image.Rect(0, 0, 20, 20), Fill: true})
+		return false
+	})
+	return m
+}
+
+func TestWidget(t *testing.T) {
+	m := widget.NewMainWindow("findMain", "test")
+	b := widget.NewButton(m, "findButton", "OK", image.Rect(10, 10, 60, 30))
+	tt := sparttest.New(t, m)
+	if w := tt.Widget("findButton"); w != b {
+		t.Errorf("widget %v, want %v", w, b)
+	}
+	if w := sparttest.Find(m, "findMain"); w != m {
+		t.Errorf("find root: %v, want %v", w, m)
+	}
+	if w := sparttest.Find(m, "none"); w != nil {
+		t.Errorf("find unknown widget: %v, want nil", w)
+	}
+	m.Close()
+}
+
+func TestClick(t *testing.T) {
+	m := widget.NewMainWindow("clickMain", "test")
+	b := widget.NewButton(m, "clickButton", "OK", image.Rect(10, 10, 60, 30))
+	b.SetProperty(widget.ButtonValue, 7)
+	tt := sparttest.New(t, m)
+	tt.Click("clickButton", sparta.MouseLeft, image.Pt(5, 5))
+	tt.ExpectCommand("clickMain", "clickButton", 7)
+	tt.ExpectCommand("clickMain", "", 7)
+	comm := tt.Commands()
+	if len(comm) != 1 {
+		t.Fatalf("commands %v, want 1 command", comm)
+	}
+	if comm[0].Dest != m || comm[0].Source != b || comm[0].Value != 7 {
+		t.Errorf("command %+v", comm[0])
+	}
+	if comm := tt.Commands(); len(comm) != 0 {
+		t.Errorf("commands after Commands: %v, want none", comm)
+	}
+	m.Close()
+}
+
+func TestExpectCommand(t *testing.T) {
+	m := widget.NewMainWindow("expectMain", "test")
+	b := widget.NewButton(m, "expectButton", "OK", image.Rect(10, 10, 60, 30))
+	b.SetProperty(widget.ButtonValue, 3)
+	r := &recorder{TB: t}
+	tt := sparttest.New(r, m)
+	tt.Click("expectButton", sparta.MouseLeft, image.Pt(5, 5))
+	tt.ExpectCommand("expectMain", "expectButton", 3)
+	if len(r.errs) != 0 {
+		t.Errorf("unexpected errors: %v", r.errs)
+	}
+	tt.ExpectCommand("expectMain", "expectButton", 4)
+	tt.ExpectCommand("expectMain", "other", 3)
+	tt.ExpectCommand("expectButton", "", 3)
+	if len(r.errs) != 3 {
+		t.Errorf("errors %v, want 3 errors", r.errs)
+	}
+	m.Close()
+}
+
+func TestKey(t *testing.T) {
+	m := widget.NewMainWindow("keyMain", "test")
+	e := widget.NewEntry(m, "keyEntry", image.Rect(10, 10, 100, 30))
+	e.SetProperty(widget.EntryValue, 5)
+	tt := sparttest.New(t, m)
+	tt.TypeString("keyEntry", "abc")
+	tt.Type("keyEntry", sparta.KeyLeft, sparta.KeyBackSpace)
+	tt.Key("keyEntry", sparta.KeyEvent{Key: 'x'})
+	if s := e.Property(widget.EntryText).(string); s != "axc" {
+		t.Errorf("text %q, want %q", s, "axc")
+	}
+	tt.Type("keyEntry", sparta.KeyReturn)
+	tt.ExpectCommand("keyMain", "keyEntry", 5)
+	m.Close()
+}
+
+func TestConfigure(t *testing.T) {
+	m := widget.NewMainWindow("configMain", "test")
+	tt := sparttest.New(t, m)
+	tt.Configure("configMain", image.Rect(0, 0, 300, 200))
+	if g := m.Property(sparta.Geometry).(image.Rectangle); g.Dx() != 300 || g.Dy() != 200 {
+		t.Errorf("geometry %v, want size 300x200", g)
+	}
+	tt.Close("configMain")
+	if m.Window() != nil {
+		t.Errorf("window not closed")
+	}
+}
+
+func TestExpectPixel(t *testing.T) {
+	m := newCanvas("pixelMain")
+	r := &recorder{TB: t}
+	tt := sparttest.New(r, m)
+	tt.ExpectPixel("pixelMainCanvas", image.Pt(5, 5), black)
+	tt.ExpectPixel("pixelMainCanvas", image.Pt(30, 30), white)
+	if len(r.errs) != 0 {
+		t.Errorf("unexpected errors: %v", r.errs)
+	}
+	tt.ExpectPixel("pixelMainCanvas", image.Pt(5, 5), white)
+	if len(r.errs) != 1 {
+		t.Errorf("errors %v, want 1 error", r.errs)
+	}
+	if c := tt.Pixel("pixelMainCanvas", image.Pt(5, 5)); c.R != 0 || c.G != 0 || c.B != 0 {
+		t.Errorf("pixel %v, want black", c)
+	}
+	m.Close()
+}
+
+func TestGoldenFile(t *testing.T) {
+	m := newCanvas("goldenFileMain")
+	tt := sparttest.New(t, m)
+	tt.Golden("goldenFileMainCanvas", "testdata/canvas.png", 0)
+	m.Close()
+}
+
+func TestGolden(t *testing.T) {
+	dir := t.TempDir()
+	m := newCanvas("goldenMain")
+	tt := sparttest.New(t, m)
+	img := tt.Image("goldenMainCanvas")
+	file := filepath.Join(dir, "canvas.png")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = png.Encode(f, img)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt.Golden("goldenMainCanvas", file, 0)
+
+	// a different image
+	other := image.NewRGBA(img.Bounds())
+	copy(other.Pix, img.Pix)
+	other.SetRGBA(30, 30, color.RGBA{R: 10, A: 255})
+	r := &recorder{TB: t}
+	sparttest.CheckGolden(r, other, file, 0)
+	if len(r.errs) != 1 {
+		t.Errorf("errors %v, want 1 error", r.errs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "canvas.got.png")); err != nil {
+		t.Errorf("got file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "canvas.diff.png")); err != nil {
+		t.Errorf("diff file: %v", err)
+	}
+
+	// within the tolerance
+	r.errs = nil
+	sparttest.CheckGolden(r, other, file, 255)
+	if len(r.errs) != 0 {
+		t.Errorf("unexpected errors: %v", r.errs)
+	}
+	m.Close()
+}
+
+func TestDiff(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	b := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	b.SetRGBA(1, 1, color.RGBA{R: 20, A: 255})
+	b.SetRGBA(2, 2, color.RGBA{R: 5})
+	diff, n := sparttest.Diff(a, b, 10)
+	if n != 1 {
+		t.Errorf("diff %d pixels, want 1", n)
+	}
+	if c := diff.RGBAAt(1, 1); c != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("diff pixel %v, want red", c)
+	}
+
+	// different sizes
+	c := image.NewRGBA(image.Rect(0, 0, 5, 4))
+	if _, n := sparttest.Diff(a, c, 0); n != 4 {
+		t.Errorf("diff %d pixels, want 4", n)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+
+	"github.com/js-arias/sparta"
+)
+
+// Attach is the attachment of an edge of a widget.
+type Attach int
+
+// Edge attachments.
+const (
+	// the edge is free.
+	AttachNone Attach = iota
+
+	// the edge keeps its distance to the left (or top) edge of the
+	// parent.
+	AttachStart
+
+	// the edge keeps its distance to the right (or bottom) edge of the
+	// parent.
+	AttachEnd
+
+	// the edge keeps its relative position in the parent.
+	AttachProp
+)
+
+// Anchors defines how the geometry of a widget changes when its parent (a
+// main window, a dialog or a canvas) is resized. If both edges of an axis
+// are attached, the widget is stretched, if only one of them is attached,
+// the widget keeps its size, and if none is attached the widget is not
+// moved in that axis.
+//
+// For example, a widget with Anchors{Left: AttachStart, Right: AttachEnd}
+// is stretched horizontally with its parent, a widget with
+// Anchors{Right: AttachEnd, Bottom: AttachEnd} is kept at the bottom right
+// corner of its parent, and a widget with all its edges attached with
+// AttachProp is scaled with its parent.
+//
+// The anchors are set with the property Anchor of the widget, and its
+// distances to the edges of the parent are taken from its geometry, and
+// the last size set for the parent, at the first resize of the parent
+// after the anchors are set.
+type Anchors struct {
+	Left, Top, Right, Bottom Attach
+}
+
+// Anchor is the property that sets the anchors (Anchors) of a widget.
+const Anchor sparta.Property = "anchor"
+
+// anchorBase is the geometry of an anchored widget, and the size of its
+// parent, used as reference when the parent is resized.
+type anchorBase struct {
+	anchors Anchors
+	size    image.Point
+	rect    image.Rectangle
+}
+
+// anchorLayout keeps the reference geometries of the anchored children of
+// a widget, and the last size of the widget.
+type anchorLayout struct {
+	size    image.Point
+	bases   map[sparta.Widget]anchorBase
+	pending bool // there are reference geometries not used in a resize
+}
+
+// SetSize sets the size requested for the widget by the program, before
+// the backend resizes it. The reference geometries of the current
+// children are taken at the previous size, so they are moved when the
+// widget is resized, while the children created after the request are
+// taken as made for the new size.
+func (l *anchorLayout) setSize(childs []sparta.Widget, size image.Point) {
+	for _, c := range childs {
+		if l.base(c, l.size) {
+			l.pending = true
+		}
+	}
+	l.size = size
+}
+
+// Base sets the reference geometry of an anchored child, if it is not
+// already set. It returns true if the reference geometry is set.
+func (l *anchorLayout) base(c sparta.Widget, size image.Point) bool {
+	if c.Window() == nil {
+		delete(l.bases, c)
+		return false
+	}
+	a, _ := c.Property(Anchor).(Anchors)
+	if a == (Anchors{}) {
+		delete(l.bases, c)
+		return false
+	}
+	if b, ok := l.bases[c]; ok && (b.anchors == a) {
+		return false
+	}
+	if l.bases == nil {
+		l.bases = make(map[sparta.Widget]anchorBase)
+	}
+	l.bases[c] = anchorBase{
+		anchors: a,
+		size:    size,
+		rect:    c.Property(sparta.Geometry).(image.Rectangle),
+	}
+	return true
+}
+
+// Resize sets the geometry of the anchored children of a widget that is
+// resized.
+func (l *anchorLayout) resize(childs []sparta.Widget, size image.Point) {
+	old := l.size
+	l.size = size
+	if old.Eq(size) && !l.pending {
+		return
+	}
+	l.pending = false
+	for _, c := range childs {
+		l.base(c, old)
+		b, ok := l.bases[c]
+		if !ok {
+			continue
+		}
+		a := b.anchors
+		var r image.Rectangle
+		r.Min.X, r.Max.X = anchorAxis(b.rect.Min.X, b.rect.Max.X, b.size.X, size.X, a.Left, a.Right)
+		r.Min.Y, r.Max.Y = anchorAxis(b.rect.Min.Y, b.rect.Max.Y, b.size.Y, size.Y, a.Top, a.Bottom)
+		c.SetProperty(sparta.Geometry, r)
+	}
+}
+
+// AnchorAxis returns the position of the edges of an anchored widget in
+// an axis, when the parent changes its size from old to cur.
+func anchorAxis(min, max, old, cur int, lo, hi Attach) (int, int) {
+	edge := func(x int, at Attach) int {
+		switch at {
+		case AttachEnd:
+			return x + cur - old
+		case AttachProp:
+			if old > 0 {
+				return (x * cur) / old
+			}
+		}
+		return x
+	}
+	w := max - min
+	switch {
+	case (lo != AttachNone) && (hi != AttachNone):
+		return edge(min, lo), edge(max, hi)
+	case hi != AttachNone:
+		m := edge(max, hi)
+		return m - w, m
+	case lo != AttachNone:
+		m := edge(min, lo)
+		return m, m + w
+	}
+	return min, max
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func expectGeometry(t *testing.T, w sparta.Widget, want image.Rectangle) {
+	t.Helper()
+	if g := w.Property(sparta.Geometry).(image.Rectangle); !g.Eq(want) {
+		t.Errorf("%v: geometry %v, want %v", w.Property(sparta.Name), g, want)
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	m := widget.NewMainWindow("anchorMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 100))
+	e := widget.NewEntry(m, "anchorEntry", image.Rect(10, 10, 190, 30))
+	e.SetProperty(widget.Anchor, widget.Anchors{Left: widget.AttachStart, Right: widget.AttachEnd})
+	b := widget.NewButton(m, "anchorButton", "OK", image.Rect(140, 60, 190, 90))
+	b.SetProperty(widget.Anchor, widget.Anchors{Right: widget.AttachEnd, Bottom: widget.AttachEnd})
+	c := widget.NewCanvas(m, "anchorCanvas", image.Rect(0, 40, 100, 60))
+	c.SetProperty(widget.Anchor, widget.Anchors{widget.AttachProp, widget.AttachProp, widget.AttachProp, widget.AttachProp})
+	tt := sparttest.New(t, m)
+	tt.Configure("anchorMain", image.Rect(0, 0, 400, 200))
+	tt.Configure("anchorMain", image.Rect(0, 0, 300, 150))
+	expectGeometry(t, e, image.Rect(10, 10, 290, 30))
+	expectGeometry(t, b, image.Rect(240, 110, 290, 140))
+	expectGeometry(t, c, image.Rect(0, 60, 150, 90))
+	m.Close()
+}
+
+// The children of a canvas are moved when the geometry of the canvas is
+// set by the client.
+func TestAnchorGeometry(t *testing.T) {
+	m := widget.NewMainWindow("anchorGeoMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 400, 200))
+	c := widget.NewCanvas(m, "anchorGeoCanvas", image.Rect(0, 0, 200, 100))
+	b := widget.NewButton(c, "anchorGeoButton", "OK", image.Rect(10, 10, 190, 30))
+	b.SetProperty(widget.Anchor, widget.Anchors{Left: widget.AttachStart, Right: widget.AttachEnd})
+	tt := sparttest.New(t, m)
+	c.SetProperty(sparta.Geometry, image.Rect(0, 0, 300, 100))
+	tt.Idle()
+	expectGeometry(t, b, image.Rect(10, 10, 290, 30))
+	m.Close()
+}
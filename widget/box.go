@@ -0,0 +1,450 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/js-arias/sparta"
+)
+
+// Box is a container that lays out its children in a row (an horizontal
+// box), or in a column (a vertical box), in the order in which they were
+// created. The children are laid out each time the box is resized.
+//
+// Each child takes its preferred size (see sparta.PrefSize, or the size
+// with which the child was created, if it does not have a preferred size)
+// along the axis of the box, and fills the box in the other axis. The
+// space left is shared between the children with a stretch factor, in
+// proportion to the factor. If there is not enough space, the children are
+// shrunk up to its minimum size, that by default is its preferred size.
+type Box struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	childs     []sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	border     bool
+	data       interface{}
+	widgetState
+
+	vertical bool
+	spacing  int
+	padding  int
+	items    map[sparta.Widget]*boxItem
+	pending  bool
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// boxItem are the layout options of a child of a box.
+type boxItem struct {
+	stretch   int
+	min, pref image.Point
+}
+
+// Box particular properties.
+const (
+	// sets the space between the children of the box (int).
+	BoxSpacing sparta.Property = "spacing"
+
+	// sets the space between the border of the box and its children
+	// (int).
+	BoxPadding = "padding"
+)
+
+// NewHBox creates a new horizontal box.
+func NewHBox(parent sparta.Widget, name string, rect image.Rectangle) *Box {
+	return newBox(parent, name, false, rect)
+}
+
+// NewVBox creates a new vertical box.
+func NewVBox(parent sparta.Widget, name string, rect image.Rectangle) *Box {
+	return newBox(parent, name, true, rect)
+}
+
+// NewBox creates a new box.
+func newBox(parent sparta.Widget, name string, vertical bool, rect image.Rectangle) *Box {
+	b := &Box{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		vertical: vertical,
+		items:    make(map[sparta.Widget]*boxItem),
+	}
+	sparta.NewWindow(b)
+	return b
+}
+
+// SetWindow is used by the backend to sets the backend window of the box.
+func (b *Box) SetWindow(win sparta.Window) {
+	b.win = win
+}
+
+// Window returns the backend window.
+func (b *Box) Window() sparta.Window {
+	return b.win
+}
+
+// RemoveWindow removes the backend window.
+func (b *Box) RemoveWindow() {
+	b.win = nil
+}
+
+// Property returns the indicated property of the box.
+func (b *Box) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Childs:
+		return b.childs
+	case sparta.Data:
+		return b.data
+	case sparta.Geometry:
+		return b.geometry
+	case sparta.Parent:
+		return b.parent
+	case sparta.Name:
+		return b.name
+	case sparta.Foreground:
+		return b.fore
+	case sparta.Background:
+		return b.back
+	case sparta.Border:
+		return b.border
+	case sparta.PrefSize:
+		return b.prefSize()
+	case BoxSpacing:
+		return b.spacing
+	case BoxPadding:
+		return b.padding
+	}
+	return b.property(p)
+}
+
+// SetProperty sets a property of the box.
+func (b *Box) SetProperty(p sparta.Property, v interface{}) {
+	if b.setProperty(b.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Childs:
+		if v == nil {
+			b.childs = nil
+			return
+		}
+		w := v.(sparta.Widget)
+		b.childs = append(b.childs, w)
+
+		// without a preferred size, the initial size is used
+		if w.Property(sparta.PrefSize) == nil {
+			b.item(w).pref = w.Property(sparta.Geometry).(image.Rectangle).Size()
+		}
+		b.relayout()
+	case sparta.Data:
+		b.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !b.geometry.Eq(val) {
+			b.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			b.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if b.name != val {
+			b.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if b.fore != val {
+			b.fore = val
+			b.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if b.back != val {
+			b.back = val
+			b.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Border:
+		val := v.(bool)
+		if b.border != val {
+			b.border = val
+			b.Update()
+		}
+	case BoxSpacing:
+		b.spacing = v.(int)
+		b.relayout()
+	case BoxPadding:
+		b.padding = v.(int)
+		b.relayout()
+	}
+}
+
+// Capture sets an event function of the box.
+func (b *Box) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		b.closeFn = fn
+	case sparta.Configure:
+		b.configFn = fn
+	case sparta.Command:
+		b.commFn = fn
+	case sparta.Expose:
+		b.exposeFn = fn
+	case sparta.KeyEv:
+		b.keyFn = fn
+	case sparta.Mouse:
+		b.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the box.
+func (b *Box) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if b.closeFn != nil {
+			b.closeFn(b, e)
+		}
+		for _, ch := range b.childs {
+			ch.OnEvent(e)
+		}
+	case sparta.ConfigureEvent:
+		b.geometry = e.(sparta.ConfigureEvent).Rect
+		if b.configFn != nil {
+			b.configFn(b, e)
+		}
+		b.Layout()
+	case sparta.CommandEvent:
+		ev := e.(sparta.CommandEvent)
+		if ev.Source == b {
+			if b.pending {
+				b.Layout()
+			}
+			return
+		}
+		if b.commFn != nil {
+			if b.commFn(b, e) {
+				return
+			}
+		}
+		b.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if b.exposeFn != nil {
+			b.exposeFn(b, e)
+		}
+		if b.border {
+			b.win.SetColor(sparta.Foreground, foreColor)
+			rect := image.Rect(0, 0, b.geometry.Dx()-1, b.geometry.Dy()-1)
+			b.win.Rectangle(rect, false)
+		}
+	case sparta.KeyEvent:
+		if b.keyFn != nil {
+			if b.keyFn(b, e) {
+				return
+			}
+		}
+		b.parent.OnEvent(e)
+	case sparta.MouseEvent:
+		if b.mouseFn != nil {
+			if b.mouseFn(b, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		ev.Loc = ev.Loc.Add(b.geometry.Min)
+		b.parent.OnEvent(ev)
+	}
+}
+
+// Update updates the box.
+func (b *Box) Update() {
+	b.win.Update()
+}
+
+// Focus set the focus on the box.
+func (b *Box) Focus() {
+	b.win.Focus()
+}
+
+// SetStretch sets the stretch factor of a child of the box. By default
+// the factor is 0, so the child keeps its preferred size.
+func (b *Box) SetStretch(w sparta.Widget, stretch int) {
+	b.item(w).stretch = stretch
+	b.relayout()
+}
+
+// SetMinSize sets the minimum size of a child of the box.
+func (b *Box) SetMinSize(w sparta.Widget, size image.Point) {
+	b.item(w).min = size
+	b.relayout()
+}
+
+// SetPrefSize sets the preferred size of a child of the box, replacing
+// the size reported by the child.
+func (b *Box) SetPrefSize(w sparta.Widget, size image.Point) {
+	b.item(w).pref = size
+	b.relayout()
+}
+
+// Item returns the layout options of a child.
+func (b *Box) item(w sparta.Widget) *boxItem {
+	it, ok := b.items[w]
+	if !ok {
+		it = &boxItem{}
+		b.items[w] = it
+	}
+	return it
+}
+
+// Relayout lays out the children of the box, after all the pending events
+// are processed.
+func (b *Box) relayout() {
+	if b.pending || (b.win == nil) {
+		return
+	}
+	b.pending = true
+	sparta.SendEvent(b, sparta.CommandEvent{Source: b})
+}
+
+// Layout sets the geometry of the children of the box.
+func (b *Box) Layout() {
+	b.pending = false
+	var ws []sparta.Widget
+	for _, c := range b.childs {
+		if c.Window() != nil {
+			ws = append(ws, c)
+		}
+	}
+	if len(ws) == 0 {
+		return
+	}
+	size := b.geometry.Size()
+	avail := b.along(size) - 2*b.padding - (len(ws)-1)*b.spacing
+	cross := b.across(size) - 2*b.padding
+	sizes := make([]int, len(ws))
+	mins := make([]int, len(ws))
+	total, stretch, shrink := 0, 0, 0
+	for i, c := range ws {
+		pref, min := b.sizes(c)
+		sizes[i] = b.along(pref)
+		mins[i] = b.along(min)
+		if mins[i] > sizes[i] {
+			mins[i] = sizes[i]
+		}
+		total += sizes[i]
+		stretch += b.item(c).stretch
+		shrink += sizes[i] - mins[i]
+	}
+	extra := avail - total
+	if (extra > 0) && (stretch > 0) {
+		last := 0
+		for i, c := range ws {
+			s := b.item(c).stretch
+			if s == 0 {
+				continue
+			}
+			d := (extra * s) / stretch
+			sizes[i] += d
+			total += d
+			last = i
+		}
+
+		// the rounding remainder goes to the last stretchable child
+		sizes[last] += avail - total
+	} else if (extra < 0) && (shrink > 0) {
+		need := -extra
+		if need > shrink {
+			need = shrink
+		}
+		for i := range ws {
+			sizes[i] -= ((sizes[i] - mins[i]) * need) / shrink
+		}
+	}
+	pos := b.padding
+	for i, c := range ws {
+		r := image.Rect(pos, b.padding, pos+sizes[i], b.padding+cross)
+		if b.vertical {
+			r = image.Rect(b.padding, pos, b.padding+cross, pos+sizes[i])
+		}
+		c.SetProperty(sparta.Geometry, r)
+		pos += sizes[i] + b.spacing
+	}
+}
+
+// Sizes returns the preferred and minimum sizes of a child.
+func (b *Box) sizes(w sparta.Widget) (pref, min image.Point) {
+	it := b.item(w)
+	pref = it.pref
+	if pref.Eq(image.ZP) {
+		pref = prefSize(w)
+	}
+	min = it.min
+	if min.Eq(image.ZP) {
+		min = pref
+	}
+	return pref, min
+}
+
+// PrefSize returns the preferred size of the box, from the preferred size
+// of its children.
+func (b *Box) prefSize() image.Point {
+	along, cross, n := 0, 0, 0
+	for _, c := range b.childs {
+		if c.Window() == nil {
+			continue
+		}
+		pref, _ := b.sizes(c)
+		along += b.along(pref)
+		if cr := b.across(pref); cr > cross {
+			cross = cr
+		}
+		n++
+	}
+	if n > 1 {
+		along += (n - 1) * b.spacing
+	}
+	along += 2 * b.padding
+	cross += 2 * b.padding
+	if b.vertical {
+		return image.Pt(cross, along)
+	}
+	return image.Pt(along, cross)
+}
+
+// Along returns the size along the axis of the box.
+func (b *Box) along(pt image.Point) int {
+	if b.vertical {
+		return pt.Y
+	}
+	return pt.X
+}
+
+// Across returns the size across the axis of the box.
+func (b *Box) across(pt image.Point) int {
+	if b.vertical {
+		return pt.X
+	}
+	return pt.Y
+}
+
+// PrefSize returns the preferred size of a widget. If the widget does not
+// have a preferred size, its current size is used.
+func prefSize(w sparta.Widget) image.Point {
+	if pt, ok := w.Property(sparta.PrefSize).(image.Point); ok {
+		return pt
+	}
+	return w.Property(sparta.Geometry).(image.Rectangle).Size()
+}
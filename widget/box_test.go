@@ -0,0 +1,80 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func TestHBox(t *testing.T) {
+	m := widget.NewMainWindow("hboxMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 300, 100))
+	b := widget.NewHBox(m, "hbox", image.Rect(0, 0, 200, 50))
+	b.SetProperty(widget.BoxPadding, 5)
+	b.SetProperty(widget.BoxSpacing, 10)
+	c1 := widget.NewCanvas(b, "hbox1", image.Rect(0, 0, 1, 1))
+	c2 := widget.NewCanvas(b, "hbox2", image.Rect(0, 0, 1, 1))
+	c3 := widget.NewCanvas(b, "hbox3", image.Rect(0, 0, 1, 1))
+	b.SetPrefSize(c1, image.Pt(20, 10))
+	b.SetPrefSize(c2, image.Pt(30, 20))
+	b.SetPrefSize(c3, image.Pt(40, 15))
+	b.SetMinSize(c1, image.Pt(10, 10))
+	b.SetMinSize(c3, image.Pt(20, 15))
+	b.SetStretch(c1, 1)
+	b.SetStretch(c3, 2)
+	tt := sparttest.New(t, m)
+
+	if pt := b.Property(sparta.PrefSize).(image.Point); !pt.Eq(image.Pt(120, 30)) {
+		t.Errorf("preferred size %v, want (120,30)", pt)
+	}
+
+	// the space left is shared by the stretch factors, and the
+	// rounding remainder goes to the last stretchable child
+	expectGeometry(t, c1, image.Rect(5, 5, 51, 45))
+	expectGeometry(t, c2, image.Rect(61, 5, 91, 45))
+	expectGeometry(t, c3, image.Rect(101, 5, 195, 45))
+
+	// the children are shrunk in proportion to the space they can
+	// lose
+	tt.Configure("hbox", image.Rect(0, 0, 100, 30))
+	expectGeometry(t, c1, image.Rect(5, 5, 19, 25))
+	expectGeometry(t, c2, image.Rect(29, 5, 59, 25))
+	expectGeometry(t, c3, image.Rect(69, 5, 96, 25))
+
+	// but not below its minimum size
+	tt.Configure("hbox", image.Rect(0, 0, 50, 30))
+	expectGeometry(t, c1, image.Rect(5, 5, 15, 25))
+	expectGeometry(t, c2, image.Rect(25, 5, 55, 25))
+	expectGeometry(t, c3, image.Rect(65, 5, 85, 25))
+	m.Close()
+}
+
+func TestVBox(t *testing.T) {
+	m := widget.NewMainWindow("vboxMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 300, 200))
+	b := widget.NewVBox(m, "vbox", image.Rect(0, 0, 100, 100))
+	c1 := widget.NewCanvas(b, "vbox1", image.Rect(0, 0, 50, 20))
+	c2 := widget.NewCanvas(b, "vbox2", image.Rect(0, 0, 30, 30))
+	c3 := widget.NewCanvas(b, "vbox3", image.Rect(0, 0, 40, 10))
+	b.SetStretch(c2, 1)
+	tt := sparttest.New(t, m)
+
+	// without a preferred size, the size of the child is used, and
+	// the children fill the width of the box
+	expectGeometry(t, c1, image.Rect(0, 0, 100, 20))
+	expectGeometry(t, c2, image.Rect(0, 20, 100, 90))
+	expectGeometry(t, c3, image.Rect(0, 90, 100, 100))
+
+	tt.Configure("vbox", image.Rect(0, 0, 60, 150))
+	expectGeometry(t, c1, image.Rect(0, 0, 60, 20))
+	expectGeometry(t, c2, image.Rect(0, 20, 60, 140))
+	expectGeometry(t, c3, image.Rect(0, 140, 60, 150))
+	m.Close()
+}
@@ -14,7 +14,7 @@ import (
 // Button is a widget that shows a text, and can be "pushed" with the mouse.
 // When a mouse button is pressed over a button widget, it will sends an
 // arbitrary value (that can be set with the propery ButtonValue) to the
-// target widget.
+// target widget. The value is also sent with the space or return keys.
 type Button struct {
 	name       string
 	win        sparta.Window
@@ -22,6 +22,7 @@ type Button struct {
 	geometry   image.Rectangle
 	fore, back color.RGBA
 	data       interface{}
+	widgetState
 
 	caption string
 	target  sparta.Widget
@@ -93,12 +94,17 @@ func (b *Button) Property(p sparta.Property) interface{} {
 		return b.target
 	case ButtonValue:
 		return b.value
+	case sparta.PrefSize:
+		return image.Pt((len([]rune(b.caption))+4)*sparta.WidthUnit, sparta.HeightUnit+8)
 	}
-	return nil
+	return b.property(p)
 }
 
 // SetProperty sets a property of the button.
 func (b *Button) SetProperty(p sparta.Property, v interface{}) {
+	if b.setProperty(b.win, p, v) {
+		return
+	}
 	switch p {
 	case sparta.Caption:
 		val := v.(string)
@@ -211,6 +217,15 @@ func (b *Button) OnEvent(e interface{}) {
 				return
 			}
 		}
+		ev := e.(sparta.KeyEvent)
+		switch ev.Key {
+		case ' ', sparta.KeyReturn, sparta.KeyPadEnter:
+			sparta.SendEvent(b.target, sparta.CommandEvent{Source: b, Value: b.value})
+		default:
+			if ev.Key > 0 {
+				b.parent.OnEvent(e)
+			}
+		}
 	case sparta.MouseEvent:
 		if sparta.IsBlock() {
 			if !sparta.IsBlocker(b) {
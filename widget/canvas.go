@@ -50,8 +50,10 @@ type Canvas struct {
 	fore, back color.RGBA
 	border     bool
 	data       interface{}
+	widgetState
 
 	onDraw, onExpose bool
+	layout           anchorLayout
 
 	closeFn  func(sparta.Widget, interface{}) bool
 	commFn   func(sparta.Widget, interface{}) bool
@@ -70,6 +72,7 @@ func NewCanvas(parent sparta.Widget, name string, rect image.Rectangle) *Canvas
 		back:     backColor,
 		fore:     foreColor,
 	}
+	c.layout.setSize(nil, c.geometry.Size())
 	sparta.NewWindow(c)
 	return c
 }
@@ -109,11 +112,14 @@ func (c *Canvas) Property(p sparta.Property) interface{} {
 	case sparta.Border:
 		return c.border
 	}
-	return nil
+	return c.property(p)
 }
 
 // SetProperty sets a property of the canvas.
 func (c *Canvas) SetProperty(p sparta.Property, v interface{}) {
+	if c.setProperty(c.win, p, v) {
+		return
+	}
 	switch p {
 	case sparta.Childs:
 		if v == nil {
@@ -126,6 +132,7 @@ func (c *Canvas) SetProperty(p sparta.Property, v interface{}) {
 	case sparta.Geometry:
 		val := v.(image.Rectangle)
 		if !c.geometry.Eq(val) {
+			c.layout.setSize(c.childs, val.Size())
 			c.win.SetProperty(sparta.Geometry, val)
 		}
 	case sparta.Parent:
@@ -187,6 +194,7 @@ func (c *Canvas) OnEvent(e interface{}) {
 		}
 	case sparta.ConfigureEvent:
 		c.geometry = e.(sparta.ConfigureEvent).Rect
+		c.layout.resize(c.childs, c.geometry.Size())
 		if c.configFn != nil {
 			c.configFn(c, e)
 		}
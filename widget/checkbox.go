@@ -0,0 +1,297 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/js-arias/sparta"
+)
+
+// CheckState is the state of a check box.
+type CheckState int
+
+// Check box states.
+const (
+	Unchecked CheckState = iota
+	Checked
+	Indeterminate
+)
+
+// CheckBox is a widget that shows a text with a box that can be checked
+// or unchecked with the mouse, or with the space key. When toggled, it
+// sends an arbitrary value (that can be set with the property
+// CheckBoxValue) to the target widget.
+//
+// The indeterminate state can only be set with the property
+// CheckBoxState, when toggled from the indeterminate state, the check box
+// will be checked.
+type CheckBox struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	widgetState
+
+	caption string
+	state   CheckState
+	target  sparta.Widget
+	value   int
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// CheckBox particular properties.
+const (
+	// sets the state of the check box (CheckState).
+	CheckBoxState sparta.Property = "state"
+
+	// sets the value (int) that the check box will send to the target
+	// when toggled.
+	CheckBoxValue = "value"
+)
+
+// NewCheckBox creates a new check box.
+func NewCheckBox(parent sparta.Widget, name, caption string, rect image.Rectangle) *CheckBox {
+	c := &CheckBox{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		caption:  caption,
+		target:   parent,
+	}
+	sparta.NewWindow(c)
+	return c
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// check box.
+func (c *CheckBox) SetWindow(win sparta.Window) {
+	c.win = win
+}
+
+// Window returns the backend window.
+func (c *CheckBox) Window() sparta.Window {
+	return c.win
+}
+
+// RemoveWindow removes the backend window.
+func (c *CheckBox) RemoveWindow() {
+	c.win = nil
+}
+
+// Property returns the indicated property of the check box.
+func (c *CheckBox) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Caption:
+		return c.caption
+	case sparta.Data:
+		return c.data
+	case sparta.Geometry:
+		return c.geometry
+	case sparta.Parent:
+		return c.parent
+	case sparta.Name:
+		return c.name
+	case sparta.Foreground:
+		return c.fore
+	case sparta.Background:
+		return c.back
+	case sparta.Target:
+		return c.target
+	case CheckBoxState:
+		return c.state
+	case CheckBoxValue:
+		return c.value
+	case sparta.PrefSize:
+		return image.Pt(12+(len([]rune(c.caption))+2)*sparta.WidthUnit, sparta.HeightUnit+4)
+	}
+	return c.property(p)
+}
+
+// SetProperty sets a property of the check box.
+func (c *CheckBox) SetProperty(p sparta.Property, v interface{}) {
+	if c.setProperty(c.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Caption:
+		val := v.(string)
+		if c.caption != val {
+			c.caption = val
+			c.Update()
+		}
+	case sparta.Data:
+		c.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !c.geometry.Eq(val) {
+			c.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			c.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if c.name != val {
+			c.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if c.fore != val {
+			c.fore = val
+			c.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if c.back != val {
+			c.back = val
+			c.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = c.parent
+		}
+		if c.target == val {
+			break
+		}
+		c.target = val
+	case CheckBoxState:
+		val := v.(CheckState)
+		if c.state != val {
+			c.state = val
+			c.Update()
+		}
+	case CheckBoxValue:
+		val := v.(int)
+		if c.value != val {
+			c.value = val
+		}
+	}
+}
+
+// Capture sets an event function of the check box.
+func (c *CheckBox) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		c.closeFn = fn
+	case sparta.Configure:
+		c.configFn = fn
+	case sparta.Command:
+		c.commFn = fn
+	case sparta.Expose:
+		c.exposeFn = fn
+	case sparta.KeyEv:
+		c.keyFn = fn
+	case sparta.Mouse:
+		c.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the check box.
+func (c *CheckBox) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if c.closeFn != nil {
+			c.closeFn(c, e)
+		}
+	case sparta.ConfigureEvent:
+		c.geometry = e.(sparta.ConfigureEvent).Rect
+		if c.configFn != nil {
+			c.configFn(c, e)
+		}
+	case sparta.CommandEvent:
+		if c.commFn != nil {
+			if c.commFn(c, e) {
+				return
+			}
+		}
+		c.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if c.exposeFn != nil {
+			c.exposeFn(c, e)
+		}
+		c.win.SetColor(sparta.Foreground, foreColor)
+		y := (c.geometry.Dy() - sparta.HeightUnit) / 2
+		box := image.Rect(2, y+2, 12, y+12)
+		c.win.Rectangle(box, false)
+		switch c.state {
+		case Checked:
+			c.win.Lines([]image.Point{image.Pt(box.Min.X+2, box.Min.Y+5), image.Pt(box.Min.X+4, box.Max.Y-2), image.Pt(box.Max.X-2, box.Min.Y+2)})
+		case Indeterminate:
+			c.win.Rectangle(box.Inset(3), true)
+		}
+		if len(c.caption) > 0 {
+			c.win.Text(image.Pt(box.Max.X+sparta.WidthUnit, y), c.caption)
+		}
+	case sparta.KeyEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(c) {
+				return
+			}
+		}
+		if c.keyFn != nil {
+			if c.keyFn(c, e) {
+				return
+			}
+		}
+		ev := e.(sparta.KeyEvent)
+		if ev.Key == ' ' {
+			c.toggle()
+			return
+		}
+		if ev.Key > 0 {
+			c.parent.OnEvent(e)
+		}
+	case sparta.MouseEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(c) {
+				return
+			}
+		}
+		if c.mouseFn != nil {
+			if c.mouseFn(c, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		if ev.Button == sparta.MouseLeft {
+			c.toggle()
+		}
+	}
+}
+
+// Toggle toggles the state of the check box.
+func (c *CheckBox) toggle() {
+	if c.state == Checked {
+		c.state = Unchecked
+	} else {
+		c.state = Checked
+	}
+	c.Update()
+	sparta.SendEvent(c.target, sparta.CommandEvent{Source: c, Value: c.value})
+}
+
+// Update updates the check box.
+func (c *CheckBox) Update() {
+	c.win.Update()
+}
+
+// Focus set the focus on the check box.
+func (c *CheckBox) Focus() {
+	c.win.Focus()
+}
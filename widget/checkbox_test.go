@@ -0,0 +1,103 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func TestCheckBox(t *testing.T) {
+	m := widget.NewMainWindow("checkMain", "test")
+	c := widget.NewCheckBox(m, "check", "Check", image.Rect(10, 10, 100, 30))
+	c.SetProperty(widget.CheckBoxValue, 3)
+	tt := sparttest.New(t, m)
+	tests := []struct {
+		click bool
+		want  widget.CheckState
+	}{
+		{true, widget.Checked},
+		{false, widget.Unchecked},
+		{false, widget.Checked},
+	}
+	for _, test := range tests {
+		tt.Commands()
+		if test.click {
+			tt.Click("check", sparta.MouseLeft, image.Pt(5, 5))
+		} else {
+			tt.Type("check", ' ')
+		}
+		tt.ExpectCommand("checkMain", "check", 3)
+		if s := c.Property(widget.CheckBoxState).(widget.CheckState); s != test.want {
+			t.Errorf("state %d, want %d", s, test.want)
+		}
+	}
+
+	// from the indeterminate state, the check box is checked
+	c.SetProperty(widget.CheckBoxState, widget.Indeterminate)
+	tt.Type("check", ' ')
+	if s := c.Property(widget.CheckBoxState).(widget.CheckState); s != widget.Checked {
+		t.Errorf("state %d, want %d", s, widget.Checked)
+	}
+	m.Close()
+}
+
+func TestRadioGroup(t *testing.T) {
+	m := widget.NewMainWindow("radioMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 100))
+	g := widget.NewRadioGroup()
+	var rb []*widget.RadioButton
+	for i, nm := range []string{"radioA", "radioB", "radioC"} {
+		r := widget.NewRadioButton(m, nm, nm, g, image.Rect(10, 10+i*20, 100, 30+i*20))
+		r.SetProperty(widget.RadioValue, i)
+		rb = append(rb, r)
+	}
+	tt := sparttest.New(t, m)
+	checked := func(want *widget.RadioButton) {
+		t.Helper()
+		if c := g.Checked(); c != want {
+			t.Errorf("checked %v, want %v", c.Property(sparta.Name), want.Property(sparta.Name))
+		}
+		for _, r := range rb {
+			if r.Window() == nil {
+				continue
+			}
+			if v := r.Property(widget.RadioChecked).(bool); v != (r == want) {
+				t.Errorf("%v checked: %v", r.Property(sparta.Name), v)
+			}
+		}
+	}
+	checked(rb[0])
+
+	tt.Click("radioC", sparta.MouseLeft, image.Pt(5, 5))
+	tt.ExpectCommand("radioMain", "radioC", 2)
+	checked(rb[2])
+	tt.Type("radioB", ' ')
+	tt.ExpectCommand("radioMain", "radioB", 1)
+	checked(rb[1])
+
+	// checking the checked button does nothing
+	tt.Commands()
+	tt.Click("radioB", sparta.MouseLeft, image.Pt(5, 5))
+	if comm := tt.Commands(); len(comm) != 0 {
+		t.Errorf("commands %v, want none", comm)
+	}
+
+	// when the checked button is removed, the first button is checked
+	rb[1].Window().Close()
+	tt.Idle()
+	checked(rb[0])
+
+	// closing the group does not send commands
+	tt.Commands()
+	m.Close()
+	if comm := tt.Commands(); len(comm) != 0 {
+		t.Errorf("commands %v, want none", comm)
+	}
+}
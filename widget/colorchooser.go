@@ -0,0 +1,443 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/js-arias/sparta"
+)
+
+// ColorChooser shows a dialog to choose a color, starting with the color
+// c. The color can be set with RGB or HSV sliders, clicking on a
+// hue/saturation map, typing its hexadecimal value (as "#rrggbb"), or
+// clicking on a palette with basic colors and the colors recently chosen.
+// The preview shows the initial color (at left) and the new color (at
+// right).
+//
+// The chosen color (color.RGBA) is the data of the dialog, and can be read
+// with the property sparta.Data. If the dialog is cancelled, it is the
+// initial color. The alpha value of the initial color is always kept.
+func ColorChooser(owner sparta.Widget, title string, c color.RGBA) *Dialog {
+	cc := &colorChooser{
+		c:   c,
+		old: c,
+	}
+	cc.h, cc.s, cc.v = rgbToHSV(c)
+
+	h := sparta.HeightUnit
+	bw := 10 * sparta.WidthUnit
+	x := 2*dialogMargin + colorMapSize
+	lw := 3 * sparta.WidthUnit
+	vw := 5 * sparta.WidthUnit
+	sw := colorChooserWidth - dialogMargin - x - lw - vw
+	py := 2*dialogMargin + colorMapSize
+	by := py + 2*colorCell + dialogMargin
+	height := by + h + 8 + dialogMargin
+
+	cc.d = NewDialog(owner, "colorChooser", title, dialogRect(owner, colorChooserWidth, height))
+	cc.d.data = c
+	cc.d.Capture(sparta.Command, cc.command)
+
+	cc.hs = NewCanvas(cc.d, "colorChooserMap", image.Rect(dialogMargin, dialogMargin, dialogMargin+colorMapSize, dialogMargin+colorMapSize))
+	cc.hs.SetProperty(sparta.Border, true)
+	cc.hs.Capture(sparta.Expose, cc.hsExpose)
+	cc.hs.Capture(sparta.Mouse, cc.hsMouse)
+
+	captions := []string{"R:", "G:", "B:", "H:", "S:", "V:"}
+	names := []string{"Red", "Green", "Blue", "Hue", "Saturation", "Value"}
+	for i := range cc.sliders {
+		y := dialogMargin + i*(h+8)
+		l := NewLabel(cc.d, "colorChooser"+names[i]+"Label", captions[i], image.Rect(x, y, x+lw, y+h))
+		l.SetProperty(LabelAlign, AlignLeft)
+		s := NewScroll(cc.d, "colorChooser"+names[i], colorMax[i]+colorPage[i], colorPage[i], Horizontal, image.Rect(x+lw, y, x+lw+sw, y+h))
+		s.Capture(sparta.Mouse, cc.slideMouse)
+		cc.sliders[i] = s
+		cc.values[i] = NewLabel(cc.d, "colorChooser"+names[i]+"Value", "", image.Rect(x+lw+sw, y, x+lw+sw+vw, y+h))
+		cc.values[i].SetProperty(LabelAlign, AlignRight)
+	}
+	y := dialogMargin + len(cc.sliders)*(h+8)
+	l := NewLabel(cc.d, "colorChooserHexLabel", "Hex:", image.Rect(x, y, x+lw+2*sparta.WidthUnit, y+h+6))
+	l.SetProperty(LabelAlign, AlignLeft)
+	cc.hex = NewEntry(cc.d, "colorChooserHex", image.Rect(x+lw+2*sparta.WidthUnit, y, x+lw+12*sparta.WidthUnit, y+h+6))
+
+	cc.palette = NewCanvas(cc.d, "colorChooserPalette", image.Rect(dialogMargin, py, dialogMargin+len(basicColors)*colorCell, py+2*colorCell))
+	cc.palette.Capture(sparta.Expose, cc.paletteExpose)
+	cc.palette.Capture(sparta.Mouse, cc.paletteMouse)
+	cc.preview = NewCanvas(cc.d, "colorChooserPreview", image.Rect(2*dialogMargin+len(basicColors)*colorCell, py, colorChooserWidth-dialogMargin, py+2*colorCell))
+	cc.preview.SetProperty(sparta.Border, true)
+	cc.preview.Capture(sparta.Expose, cc.previewExpose)
+
+	cc.ok = NewButton(cc.d, "colorChooserOK", "OK", image.Rect(colorChooserWidth-dialogMargin-2*bw-dialogMargin, by, colorChooserWidth-2*dialogMargin-bw, by+h+8))
+	cancel := NewButton(cc.d, "colorChooserCancel", "Cancel", image.Rect(colorChooserWidth-dialogMargin-bw, by, colorChooserWidth-dialogMargin, by+h+8))
+	cancel.SetProperty(ButtonValue, DialogCancel)
+
+	cc.sync()
+	cc.d.Focus()
+	return cc.d
+}
+
+// color chooser sizes
+const (
+	colorChooserWidth = 440
+	colorMapSize      = 180
+	colorMapBlock     = 6
+	colorCell         = 20
+)
+
+// maximum values, and page sizes of the color sliders
+var (
+	colorMax  = [6]int{255, 255, 255, 359, 100, 100}
+	colorPage = [6]int{16, 16, 16, 16, 8, 8}
+)
+
+// basicColors are the basic colors shown in the color chooser palette.
+var basicColors = []color.RGBA{
+	{R: 0, G: 0, B: 0, A: 255},
+	{R: 128, G: 128, B: 128, A: 255},
+	{R: 192, G: 192, B: 192, A: 255},
+	{R: 255, G: 255, B: 255, A: 255},
+	{R: 128, G: 0, B: 0, A: 255},
+	{R: 255, G: 0, B: 0, A: 255},
+	{R: 128, G: 128, B: 0, A: 255},
+	{R: 255, G: 255, B: 0, A: 255},
+	{R: 0, G: 128, B: 0, A: 255},
+	{R: 0, G: 255, B: 0, A: 255},
+	{R: 0, G: 128, B: 128, A: 255},
+	{R: 0, G: 255, B: 255, A: 255},
+	{R: 0, G: 0, B: 128, A: 255},
+	{R: 0, G: 0, B: 255, A: 255},
+	{R: 128, G: 0, B: 128, A: 255},
+	{R: 255, G: 0, B: 255, A: 255},
+}
+
+// recentColors are the colors chosen recently, the most recent first.
+var recentColors []color.RGBA
+
+// colorChooser keeps the state of a color chooser dialog.
+type colorChooser struct {
+	d       *Dialog
+	c, old  color.RGBA
+	h, s, v int
+
+	sliders [6]*Scroll
+	last    [6]int // values of the sliders set by the dialog
+	values  [6]*Label
+	hex     *Entry
+	hs      *Canvas
+	palette *Canvas
+	preview *Canvas
+	ok      *Button
+}
+
+// Command process the command events of the color chooser dialog.
+func (cc *colorChooser) command(w sparta.Widget, e interface{}) bool {
+	ev := e.(sparta.CommandEvent)
+	for i, s := range cc.sliders {
+		if ev.Source != s {
+			continue
+		}
+		// the sliders also send its value when they are set by
+		// the dialog, and as the events are queued, an event can
+		// be older than the current position of the slider.
+		if (ev.Value == s.Property(ScrollPos).(int)) && (ev.Value != cc.last[i]) {
+			cc.set(i, ev.Value)
+		}
+		return true
+	}
+	switch ev.Source {
+	case cc.hex:
+		if c, ok := parseColor(cc.hex.Property(EntryText).(string)); ok {
+			cc.setRGB(c)
+		} else {
+			cc.hex.SetProperty(EntryText, hexColor(cc.c))
+		}
+	case cc.ok:
+		cc.done()
+	default:
+		return false
+	}
+	return true
+}
+
+// Done closes the dialog with the current color.
+func (cc *colorChooser) done() {
+	c := cc.c
+	c.A = 255
+	for i, r := range recentColors {
+		if r == c {
+			recentColors = append(recentColors[:i], recentColors[i+1:]...)
+			break
+		}
+	}
+	recentColors = append([]color.RGBA{c}, recentColors...)
+	if len(recentColors) > len(basicColors) {
+		recentColors = recentColors[:len(basicColors)]
+	}
+	cc.d.data = cc.c
+	cc.d.Done(DialogOK)
+}
+
+// Value returns the value of the i-th slider.
+func (cc *colorChooser) value(i int) int {
+	switch i {
+	case 0:
+		return int(cc.c.R)
+	case 1:
+		return int(cc.c.G)
+	case 2:
+		return int(cc.c.B)
+	case 3:
+		return cc.h
+	case 4:
+		return cc.s
+	}
+	return cc.v
+}
+
+// Set sets the value of the i-th slider.
+func (cc *colorChooser) set(i, v int) {
+	c := cc.c
+	switch i {
+	case 0:
+		c.R = uint8(v)
+	case 1:
+		c.G = uint8(v)
+	case 2:
+		c.B = uint8(v)
+	case 3:
+		cc.setHSV(v, cc.s, cc.v)
+		return
+	case 4:
+		cc.setHSV(cc.h, v, cc.v)
+		return
+	case 5:
+		cc.setHSV(cc.h, cc.s, v)
+		return
+	}
+	cc.setRGB(c)
+}
+
+// SetRGB sets the current color from a RGB color.
+func (cc *colorChooser) setRGB(c color.RGBA) {
+	c.A = cc.old.A
+	cc.c = c
+	h, s, v := rgbToHSV(c)
+
+	// hue and saturation are undefined for grays and black
+	if s > 0 {
+		cc.h = h
+	}
+	if v > 0 {
+		cc.s = s
+	}
+	cc.v = v
+	cc.sync()
+}
+
+// SetHSV sets the current color from its hue, saturation and value.
+func (cc *colorChooser) setHSV(h, s, v int) {
+	cc.h, cc.s, cc.v = h, s, v
+	cc.c = hsvToRGB(h, s, v)
+	cc.c.A = cc.old.A
+	cc.sync()
+}
+
+// Sync shows the current color in the widgets of the dialog.
+func (cc *colorChooser) sync() {
+	for i, s := range cc.sliders {
+		v := cc.value(i)
+		cc.last[i] = v
+		s.SetProperty(ScrollPos, v)
+		cc.values[i].SetProperty(sparta.Caption, strconv.Itoa(v))
+	}
+	cc.hex.SetProperty(EntryText, hexColor(cc.c))
+	cc.hs.Update()
+	cc.preview.Update()
+}
+
+// SlideMouse allows to drag the color sliders.
+func (cc *colorChooser) slideMouse(w sparta.Widget, e interface{}) bool {
+	ev := e.(sparta.MouseEvent)
+	if (ev.Button != 0) || ((ev.State & sparta.StateButtonL) == 0) {
+		return false
+	}
+	s := w.(*Scroll)
+	s.SetProperty(ScrollPos, (ev.Loc.X*s.size)/s.geometry.Dx())
+	return true
+}
+
+// HsExpose draws the hue/saturation map.
+func (cc *colorChooser) hsExpose(w sparta.Widget, e interface{}) bool {
+	cv := w.(*Canvas)
+	for y := 0; y < colorMapSize; y += colorMapBlock {
+		for x := 0; x < colorMapSize; x += colorMapBlock {
+			cv.SetColor(sparta.Foreground, hsvToRGB((x*360)/colorMapSize, 100-(y*100)/colorMapSize, 100))
+			cv.Draw(Rectangle{Rect: image.Rect(x, y, x+colorMapBlock, y+colorMapBlock), Fill: true})
+		}
+	}
+	x := (cc.h * colorMapSize) / 360
+	y := ((100 - cc.s) * colorMapSize) / 100
+	cv.SetColor(sparta.Foreground, foreColor)
+	cv.Draw([]image.Point{image.Pt(x-6, y), image.Pt(x-2, y)})
+	cv.Draw([]image.Point{image.Pt(x+2, y), image.Pt(x+6, y)})
+	cv.Draw([]image.Point{image.Pt(x, y-6), image.Pt(x, y-2)})
+	cv.Draw([]image.Point{image.Pt(x, y+2), image.Pt(x, y+6)})
+	return false
+}
+
+// HsMouse sets the hue and saturation with the mouse.
+func (cc *colorChooser) hsMouse(w sparta.Widget, e interface{}) bool {
+	ev := e.(sparta.MouseEvent)
+	if (ev.Button != sparta.MouseLeft) && ((ev.Button != 0) || ((ev.State & sparta.StateButtonL) == 0)) {
+		return true
+	}
+	x, y := ev.Loc.X, ev.Loc.Y
+	if x < 0 {
+		x = 0
+	} else if x >= colorMapSize {
+		x = colorMapSize - 1
+	}
+	if y < 0 {
+		y = 0
+	} else if y > colorMapSize {
+		y = colorMapSize
+	}
+	cc.setHSV((x*360)/colorMapSize, 100-(y*100)/colorMapSize, cc.v)
+	return true
+}
+
+// PaletteExpose draws the basic colors and the recent colors.
+func (cc *colorChooser) paletteExpose(w sparta.Widget, e interface{}) bool {
+	cv := w.(*Canvas)
+	for row, cls := range [][]color.RGBA{basicColors, recentColors} {
+		for i, c := range cls {
+			rect := image.Rect(i*colorCell+2, row*colorCell+2, (i+1)*colorCell-2, (row+1)*colorCell-2)
+			cv.SetColor(sparta.Foreground, c)
+			cv.Draw(Rectangle{Rect: rect, Fill: true})
+			cv.SetColor(sparta.Foreground, foreColor)
+			cv.Draw(Rectangle{Rect: rect})
+		}
+	}
+	return false
+}
+
+// PaletteMouse sets the color clicked in the palette.
+func (cc *colorChooser) paletteMouse(w sparta.Widget, e interface{}) bool {
+	ev := e.(sparta.MouseEvent)
+	if ev.Button != sparta.MouseLeft {
+		return true
+	}
+	i, row := ev.Loc.X/colorCell, ev.Loc.Y/colorCell
+	cls := basicColors
+	if row > 0 {
+		cls = recentColors
+	}
+	if (i >= 0) && (i < len(cls)) {
+		cc.setRGB(cls[i])
+	}
+	return true
+}
+
+// PreviewExpose draws the initial and the current colors.
+func (cc *colorChooser) previewExpose(w sparta.Widget, e interface{}) bool {
+	cv := w.(*Canvas)
+	r := cv.geometry
+	cv.SetColor(sparta.Foreground, cc.old)
+	cv.Draw(Rectangle{Rect: image.Rect(0, 0, r.Dx()/2, r.Dy()), Fill: true})
+	cv.SetColor(sparta.Foreground, cc.c)
+	cv.Draw(Rectangle{Rect: image.Rect(r.Dx()/2, 0, r.Dx(), r.Dy()), Fill: true})
+	return false
+}
+
+// HexColor returns the hexadecimal value of a color.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// ParseColor reads a color in hexadecimal, as "#rrggbb" or "#rgb".
+func parseColor(s string) (color.RGBA, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return color.RGBA{}, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, true
+}
+
+// HsvToRGB converts a color from HSV (with the hue in degrees, and the
+// saturation and value in percent) to RGB.
+func hsvToRGB(h, s, v int) color.RGBA {
+	vv := float64(v) * 255 / 100
+	if s == 0 {
+		g := uint8(vv + 0.5)
+		return color.RGBA{R: g, G: g, B: g, A: 255}
+	}
+	hh := float64(h%360) / 60
+	i := int(hh)
+	f := hh - float64(i)
+	ss := float64(s) / 100
+	p := vv * (1 - ss)
+	q := vv * (1 - ss*f)
+	t := vv * (1 - ss*(1-f))
+	var r, g, b float64
+	switch i {
+	case 0:
+		r, g, b = vv, t, p
+	case 1:
+		r, g, b = q, vv, p
+	case 2:
+		r, g, b = p, vv, t
+	case 3:
+		r, g, b = p, q, vv
+	case 4:
+		r, g, b = t, p, vv
+	default:
+		r, g, b = vv, p, q
+	}
+	return color.RGBA{R: uint8(r + 0.5), G: uint8(g + 0.5), B: uint8(b + 0.5), A: 255}
+}
+
+// RgbToHSV converts a color from RGB to HSV (with the hue in degrees, and
+// the saturation and value in percent).
+func rgbToHSV(c color.RGBA) (h, s, v int) {
+	r, g, b := float64(c.R), float64(c.G), float64(c.B)
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	v = int(max*100/255 + 0.5)
+	if max == 0 {
+		return 0, 0, v
+	}
+	d := max - min
+	s = int(d*100/max + 0.5)
+	if d == 0 {
+		return 0, s, v
+	}
+	var hh float64
+	switch max {
+	case r:
+		hh = (g - b) / d
+		if hh < 0 {
+			hh += 6
+		}
+	case g:
+		hh = (b-r)/d + 2
+	default:
+		hh = (r-g)/d + 4
+	}
+	h = int(hh*60+0.5) % 360
+	return h, s, v
+}
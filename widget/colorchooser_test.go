@@ -0,0 +1,111 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/headless"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+// SetHex types a color in the hexadecimal entry of a color chooser.
+func setHex(tt *sparttest.Tester, hex string) {
+	tt.Type("colorChooserHex", sparta.KeyEnd)
+	n := len(tt.Widget("colorChooserHex").Property(widget.EntryText).(string))
+	for i := 0; i < n; i++ {
+		tt.Type("colorChooserHex", sparta.KeyBackSpace)
+	}
+	tt.TypeString("colorChooserHex", hex)
+	tt.Type("colorChooserHex", sparta.KeyReturn)
+}
+
+func expectSlider(t *testing.T, tt *sparttest.Tester, name string, want int) {
+	t.Helper()
+	if v := tt.Widget("colorChooser" + name).Property(widget.ScrollPos).(int); v != want {
+		t.Errorf("%s slider %d, want %d", name, v, want)
+	}
+	if s := tt.Widget("colorChooser" + name + "Value").Property(sparta.Caption).(string); s != fmt.Sprint(want) {
+		t.Errorf("%s value %q, want %d", name, s, want)
+	}
+}
+
+func expectHex(t *testing.T, tt *sparttest.Tester, want string) {
+	t.Helper()
+	if s := tt.Widget("colorChooserHex").Property(widget.EntryText).(string); s != want {
+		t.Errorf("hex %q, want %q", s, want)
+	}
+}
+
+func TestColorChooser(t *testing.T) {
+	m := widget.NewMainWindow("colorMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 400, 300))
+	tt := sparttest.New(t, m)
+	n := len(headless.Windows())
+	d := widget.ColorChooser(m, "Color", color.RGBA{R: 10, G: 20, B: 30, A: 128})
+	tt.Idle()
+	expectHex(t, tt, "#0a141e")
+	expectSlider(t, tt, "Red", 10)
+
+	// the hexadecimal entry sets the sliders
+	setHex(tt, "#f00")
+	expectHex(t, tt, "#ff0000")
+	expectSlider(t, tt, "Red", 255)
+	expectSlider(t, tt, "Green", 0)
+	expectSlider(t, tt, "Hue", 0)
+	expectSlider(t, tt, "Saturation", 100)
+	expectSlider(t, tt, "Value", 100)
+
+	// an invalid value is replaced by the current color
+	setHex(tt, "#zz")
+	expectHex(t, tt, "#ff0000")
+
+	// a slider sets the color
+	s := tt.Widget("colorChooserGreen")
+	w := s.Property(sparta.Geometry).(image.Rectangle).Dx()
+	tt.Mouse("colorChooserGreen", sparta.MouseEvent{State: sparta.StateButtonL, Loc: image.Pt(w/2, 5)})
+	g := s.Property(widget.ScrollPos).(int)
+	if g == 0 {
+		t.Fatalf("green slider not moved")
+	}
+	expectHex(t, tt, fmt.Sprintf("#ff%02x00", g))
+	expectSlider(t, tt, "Red", 255)
+
+	// the value slider scales the color
+	tt.Widget("colorChooserValue").SetProperty(widget.ScrollPos, 0)
+	tt.Idle()
+	expectHex(t, tt, "#000000")
+	expectSlider(t, tt, "Red", 0)
+
+	setHex(tt, "#336699")
+	tt.Click("colorChooserOK", sparta.MouseLeft, image.Pt(5, 5))
+	if len(headless.Windows()) != n {
+		t.Fatalf("color chooser not closed")
+	}
+	if c := d.Property(sparta.Data).(color.RGBA); c != (color.RGBA{R: 0x33, G: 0x66, B: 0x99, A: 128}) {
+		t.Errorf("color %v, want #336699 with the initial alpha", c)
+	}
+	tt.ExpectCommand("colorMain", "colorChooser", widget.DialogOK)
+
+	// cancel keeps the initial color
+	old := color.RGBA{R: 1, G: 2, B: 3, A: 255}
+	d = widget.ColorChooser(m, "Color", old)
+	tt.Idle()
+	setHex(tt, "#ffffff")
+	tt.Click("colorChooserCancel", sparta.MouseLeft, image.Pt(5, 5))
+	if len(headless.Windows()) != n {
+		t.Fatalf("color chooser not closed")
+	}
+	if c := d.Property(sparta.Data).(color.RGBA); c != old {
+		t.Errorf("color %v after cancel, want %v", c, old)
+	}
+	tt.ExpectCommand("colorMain", "colorChooser", widget.DialogCancel)
+	m.Close()
+}
@@ -0,0 +1,549 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"unicode"
+
+	"github.com/js-arias/sparta"
+)
+
+// comboRows is the maximum number of rows shown in the popup list of a
+// combo box.
+const comboRows = 8
+
+// ComboBox is a widget that shows the selected element of a list. When
+// clicked (or with the space or return keys) it opens a popup list below
+// it, from which an element can be selected with the mouse, or with the
+// arrow and return keys. The popup is closed when an element is selected,
+// with the escape key, or with a click outside of the popup.
+//
+// When the selected element changes, the combo box sends a command event to
+// its target widget indicating the index of the selected element.
+//
+// If the filter is enabled (with the property ComboFilter), the text
+// typed when the popup is open is used to show only the elements that
+// contain it.
+type ComboBox struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	widgetState
+
+	list   ListData
+	sel    int
+	filter bool
+	target sparta.Widget
+
+	// popup
+	popup *Popup
+	plist *List
+	view  *comboView
+	typed []rune
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// ComboBox particular properties.
+const (
+	// sets the string list (ListData). The first element for which IsSel
+	// is true is selected.
+	ComboList sparta.Property = "list"
+
+	// sets the index of the selected element (int), -1 if there is no
+	// selected element.
+	ComboSel = "selected"
+
+	// enables the filtering of the list with the typed text (bool).
+	ComboFilter = "filter"
+)
+
+// NewComboBox creates a new combo box.
+func NewComboBox(parent sparta.Widget, name string, rect image.Rectangle) *ComboBox {
+	c := &ComboBox{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		sel:      -1,
+		target:   parent,
+	}
+	sparta.NewWindow(c)
+	return c
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// combo box.
+func (c *ComboBox) SetWindow(win sparta.Window) {
+	c.win = win
+}
+
+// Window returns the backend window.
+func (c *ComboBox) Window() sparta.Window {
+	return c.win
+}
+
+// RemoveWindow removes the backend window.
+func (c *ComboBox) RemoveWindow() {
+	c.win = nil
+	c.close()
+}
+
+// Property returns the indicated property of the combo box.
+func (c *ComboBox) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Data:
+		return c.data
+	case sparta.Geometry:
+		return c.geometry
+	case sparta.Parent:
+		return c.parent
+	case sparta.Name:
+		return c.name
+	case sparta.Foreground:
+		return c.fore
+	case sparta.Background:
+		return c.back
+	case sparta.Target:
+		return c.target
+	case ComboList:
+		return c.list
+	case ComboSel:
+		return c.sel
+	case ComboFilter:
+		return c.filter
+	case sparta.PrefSize:
+		return c.prefSize()
+	}
+	return c.property(p)
+}
+
+// SetProperty sets a property of the combo box.
+func (c *ComboBox) SetProperty(p sparta.Property, v interface{}) {
+	if c.setProperty(c.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Data:
+		c.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !c.geometry.Eq(val) {
+			c.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			c.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if c.name != val {
+			c.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if c.fore != val {
+			c.fore = val
+			c.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if c.back != val {
+			c.back = val
+			c.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = c.parent
+		}
+		if c.target == val {
+			break
+		}
+		c.target = val
+	case ComboList:
+		c.close()
+		c.list = nil
+		c.sel = -1
+		if v != nil {
+			c.list = v.(ListData)
+			for i := 0; i < c.list.Len(); i++ {
+				if c.list.IsSel(i) {
+					c.sel = i
+					break
+				}
+			}
+		}
+		c.Update()
+	case ComboSel:
+		val := v.(int)
+		if (c.list == nil) || (val < 0) || (val >= c.list.Len()) {
+			val = -1
+		}
+		if c.sel != val {
+			c.sel = val
+			c.Update()
+		}
+	case ComboFilter:
+		val := v.(bool)
+		if c.filter != val {
+			c.filter = val
+		}
+	}
+}
+
+// Capture sets an event function of the combo box.
+func (c *ComboBox) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		c.closeFn = fn
+	case sparta.Configure:
+		c.configFn = fn
+	case sparta.Command:
+		c.commFn = fn
+	case sparta.Expose:
+		c.exposeFn = fn
+	case sparta.KeyEv:
+		c.keyFn = fn
+	case sparta.Mouse:
+		c.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the combo box.
+func (c *ComboBox) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if c.closeFn != nil {
+			c.closeFn(c, e)
+		}
+	case sparta.ConfigureEvent:
+		c.geometry = e.(sparta.ConfigureEvent).Rect
+		if c.configFn != nil {
+			c.configFn(c, e)
+		}
+	case sparta.CommandEvent:
+		if c.commFn != nil {
+			if c.commFn(c, e) {
+				return
+			}
+		}
+		ev := e.(sparta.CommandEvent)
+		if c.popup != nil {
+			if ev.Source == c.popup {
+				c.close()
+				return
+			}
+			if ev.Source == c.plist {
+				c.choose(ev.Value)
+				return
+			}
+		}
+		c.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if c.exposeFn != nil {
+			c.exposeFn(c, e)
+		}
+		c.win.SetColor(sparta.Foreground, foreColor)
+		y := (c.geometry.Dy() - sparta.HeightUnit) / 2
+		txt := ""
+		if len(c.typed) > 0 {
+			txt = string(c.typed)
+		} else if c.sel >= 0 {
+			txt = c.list.Item(c.sel)
+		}
+		if cols := (c.geometry.Dx() - 20) / sparta.WidthUnit; len([]rune(txt)) > cols {
+			if cols < 0 {
+				cols = 0
+			}
+			txt = string([]rune(txt)[:cols])
+		}
+		c.win.Text(image.Pt(2, y), txt)
+		x := c.geometry.Dx() - 15
+		c.win.Lines([]image.Point{image.Pt(x, 0), image.Pt(x, c.geometry.Dy()-1)})
+		y = c.geometry.Dy() / 2
+		c.win.Polygon([]image.Point{image.Pt(x+3, y-2), image.Pt(x+11, y-2), image.Pt(x+7, y+2)}, true)
+		rect := image.Rect(0, 0, c.geometry.Dx()-1, c.geometry.Dy()-1)
+		c.win.Rectangle(rect, false)
+	case sparta.KeyEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(c) {
+				return
+			}
+		}
+		if c.keyFn != nil {
+			if c.keyFn(c, e) {
+				return
+			}
+		}
+		ev := e.(sparta.KeyEvent)
+		switch ev.Key {
+		case ' ', sparta.KeyReturn, sparta.KeyPadEnter:
+			c.open()
+		case sparta.KeyDown:
+			c.setSel(c.sel + 1)
+		case sparta.KeyUp:
+			if c.sel > 0 {
+				c.setSel(c.sel - 1)
+			}
+		default:
+			if ev.Key > 0 {
+				c.parent.OnEvent(e)
+			}
+		}
+	case sparta.MouseEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(c) {
+				return
+			}
+		}
+		if c.mouseFn != nil {
+			if c.mouseFn(c, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		if ev.Button == sparta.MouseLeft {
+			c.open()
+		}
+	}
+}
+
+// SetSel sets the selected element, and if it changes, sends the index to
+// the target.
+func (c *ComboBox) setSel(sel int) {
+	if (c.list == nil) || (sel < 0) || (sel >= c.list.Len()) || (sel == c.sel) {
+		return
+	}
+	c.sel = sel
+	c.Update()
+	sparta.SendEvent(c.target, sparta.CommandEvent{Source: c, Value: c.sel})
+}
+
+// Open opens the popup list.
+func (c *ComboBox) open() {
+	if (c.popup != nil) || (c.list == nil) {
+		return
+	}
+	c.typed = nil
+	c.view = &comboView{c: c}
+	rows := c.list.Len()
+	if rows > comboRows {
+		rows = comboRows
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	pt := sparta.RootPos(c).Add(image.Pt(0, c.geometry.Dy()))
+	rect := image.Rect(pt.X, pt.Y, pt.X+c.geometry.Dx(), pt.Y+(rows*sparta.HeightUnit)+4)
+	c.popup = NewPopup(c, "combo"+c.name+"Popup", rect)
+	c.plist = NewList(c.popup, "combo"+c.name+"List", image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	c.plist.Capture(sparta.KeyEv, c.listKey)
+	c.plist.Capture(sparta.Mouse, c.listMouse)
+	c.filterView()
+	sparta.Grab(c.popup)
+	c.plist.Focus()
+}
+
+// Close closes the popup list.
+func (c *ComboBox) close() {
+	if c.popup == nil {
+		return
+	}
+	sparta.Ungrab()
+	p := c.popup
+	c.popup = nil
+	c.plist = nil
+	c.view = nil
+	c.typed = nil
+	p.Close()
+	if c.win != nil {
+		c.Update()
+		c.Focus()
+	}
+}
+
+// Choose selects an element of the popup list, and closes the popup.
+func (c *ComboBox) choose(i int) {
+	if (i < 0) || (i >= c.view.Len()) {
+		return
+	}
+	sel := c.view.items[i]
+	c.close()
+	c.setSel(sel)
+}
+
+// FilterView sets the elements shown in the popup list.
+func (c *ComboBox) filterView() {
+	v := c.view
+	v.items = v.items[:0]
+	txt := strings.ToLower(string(c.typed))
+	for i := 0; i < c.list.Len(); i++ {
+		if (len(txt) > 0) && !strings.Contains(strings.ToLower(c.list.Item(i)), txt) {
+			continue
+		}
+		v.items = append(v.items, i)
+	}
+	v.cur = 0
+	for j, i := range v.items {
+		if i == c.sel {
+			v.cur = j
+			break
+		}
+	}
+	c.plist.SetProperty(ListList, v)
+	c.showCur()
+	c.Update()
+}
+
+// ShowCur scrolls the popup list to show the current element.
+func (c *ComboBox) showCur() {
+	pos := c.plist.scroll.Property(ScrollPos).(int)
+	page := c.plist.geometry.Dy() / sparta.HeightUnit
+	if c.view.cur < pos {
+		c.plist.scroll.SetProperty(ScrollPos, c.view.cur)
+	} else if c.view.cur >= pos+page {
+		c.plist.scroll.SetProperty(ScrollPos, c.view.cur-page+1)
+	}
+}
+
+// MoveCur moves the current element of the popup list.
+func (c *ComboBox) moveCur(cur int) {
+	if cur >= c.view.Len() {
+		cur = c.view.Len() - 1
+	}
+	if cur < 0 {
+		cur = 0
+	}
+	if c.view.cur == cur {
+		return
+	}
+	c.view.cur = cur
+	c.showCur()
+	c.plist.Update()
+}
+
+// ListKey process the key events of the popup list.
+func (c *ComboBox) listKey(w sparta.Widget, e interface{}) bool {
+	ev := e.(sparta.KeyEvent)
+	if ev.Key < 0 {
+		return true
+	}
+	switch ev.Key {
+	case sparta.KeyEscape:
+		c.close()
+	case sparta.KeyReturn, sparta.KeyPadEnter:
+		c.choose(c.view.cur)
+	case sparta.KeyDown:
+		c.moveCur(c.view.cur + 1)
+	case sparta.KeyUp:
+		c.moveCur(c.view.cur - 1)
+	case sparta.KeyPageDown:
+		c.moveCur(c.view.cur + comboRows)
+	case sparta.KeyPageUp:
+		c.moveCur(c.view.cur - comboRows)
+	case sparta.KeyHome:
+		c.moveCur(0)
+	case sparta.KeyEnd:
+		c.moveCur(c.view.Len() - 1)
+	case sparta.KeyBackSpace:
+		if !c.filter || (len(c.typed) == 0) {
+			break
+		}
+		c.typed = c.typed[:len(c.typed)-1]
+		c.filterView()
+	default:
+		if !c.filter {
+			break
+		}
+		if ((ev.Key & sparta.KeyNoChar) != 0) || ((ev.State & sparta.StateCtrl) != 0) {
+			break
+		}
+		r := rune(ev.Key)
+		if !unicode.IsPrint(r) {
+			break
+		}
+		c.typed = append(c.typed, r)
+		c.filterView()
+	}
+	return true
+}
+
+// ListMouse process the mouse events of the popup list, the current
+// element follows the pointer.
+func (c *ComboBox) listMouse(w sparta.Widget, e interface{}) bool {
+	ev := e.(sparta.MouseEvent)
+	if ev.Button != 0 {
+		return false
+	}
+	pos := c.plist.scroll.Property(ScrollPos).(int)
+	if pos < 0 {
+		pos = 0
+	}
+	p := ((ev.Loc.Y - 2) / sparta.HeightUnit) + pos
+	if (p >= 0) && (p < c.view.Len()) {
+		c.moveCur(p)
+	}
+	return true
+}
+
+// PrefSize returns a size in which the longest element of the list is
+// shown.
+func (c *ComboBox) prefSize() image.Point {
+	w := 10
+	if c.list != nil {
+		for i := 0; i < c.list.Len(); i++ {
+			if n := len([]rune(c.list.Item(i))); n > w {
+				w = n
+			}
+		}
+	}
+	return image.Pt(w*sparta.WidthUnit+20, sparta.HeightUnit+6)
+}
+
+// Update updates the combo box.
+func (c *ComboBox) Update() {
+	c.win.Update()
+}
+
+// Focus set the focus on the combo box.
+func (c *ComboBox) Focus() {
+	c.win.Focus()
+}
+
+// ComboView is the list of elements shown in the popup list of a combo
+// box.
+type comboView struct {
+	c     *ComboBox
+	items []int // index of the shown elements
+	cur   int   // current element
+}
+
+// Len returns the number of shown elements.
+func (v *comboView) Len() int {
+	return len(v.items)
+}
+
+// Item returns the name of the i-th shown element.
+func (v *comboView) Item(i int) string {
+	return v.c.list.Item(v.items[i])
+}
+
+// IsSel returns true if the i-th shown element is the current element.
+func (v *comboView) IsSel(i int) bool {
+	return i == v.cur
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/headless"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func expectComboSel(t *testing.T, c *widget.ComboBox, want int) {
+	t.Helper()
+	if s := c.Property(widget.ComboSel).(int); s != want {
+		t.Errorf("selected %d, want %d", s, want)
+	}
+}
+
+func TestComboBox(t *testing.T) {
+	m := widget.NewMainWindow("comboMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 200))
+	c := widget.NewComboBox(m, "combo", image.Rect(10, 10, 150, 30))
+	c.SetProperty(widget.ComboList, items{"red", "green", "blue", "black"})
+	tt := sparttest.New(t, m)
+	n := len(headless.Windows())
+	expectComboSel(t, c, -1)
+
+	// select with the keyboard
+	tt.Type("combo", ' ')
+	if len(headless.Windows()) != n+1 {
+		t.Fatalf("popup not opened")
+	}
+	if f := sparta.Focused(); f != tt.Widget("combocomboList") {
+		t.Errorf("focus %v, want the popup list", f)
+	}
+	tt.Commands()
+	tt.Type("combocomboList", sparta.KeyDown, sparta.KeyDown, sparta.KeyReturn)
+	tt.ExpectCommand("comboMain", "combo", 2)
+	expectComboSel(t, c, 2)
+	if len(headless.Windows()) != n {
+		t.Errorf("popup not closed")
+	}
+
+	// select with the mouse
+	tt.Click("combo", sparta.MouseLeft, image.Pt(5, 5))
+	tt.Click("combocomboList", sparta.MouseLeft, rowPt(1))
+	tt.ExpectCommand("comboMain", "combo", 1)
+	expectComboSel(t, c, 1)
+
+	// the popup grabs the pointer, so a click outside closes it
+	tt.Click("combo", sparta.MouseLeft, image.Pt(5, 5))
+	tt.Commands()
+	tt.Click("comboMain", sparta.MouseLeft, image.Pt(190, 190))
+	if len(headless.Windows()) != n {
+		t.Errorf("popup not closed by a click outside")
+	}
+	for _, cm := range tt.Commands() {
+		if cm.Source == sparta.Widget(c) {
+			t.Errorf("command %+v from the combo box", cm)
+		}
+	}
+	expectComboSel(t, c, 1)
+
+	// escape closes without changes
+	tt.Type("combo", sparta.KeyReturn)
+	tt.Type("combocomboList", sparta.KeyDown, sparta.KeyEscape)
+	if len(headless.Windows()) != n {
+		t.Errorf("popup not closed by escape")
+	}
+	expectComboSel(t, c, 1)
+
+	// arrow keys on the closed combo box
+	tt.Type("combo", sparta.KeyDown)
+	expectComboSel(t, c, 2)
+	m.Close()
+}
+
+func TestComboBoxFilter(t *testing.T) {
+	m := widget.NewMainWindow("comboFilterMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 200))
+	c := widget.NewComboBox(m, "comboFilter", image.Rect(10, 10, 150, 30))
+	c.SetProperty(widget.ComboList, items{"red", "green", "blue", "black"})
+	c.SetProperty(widget.ComboFilter, true)
+	tt := sparttest.New(t, m)
+	tt.Type("comboFilter", ' ')
+	tt.TypeString("combocomboFilterList", "bl")
+	tt.Type("combocomboFilterList", sparta.KeyDown, sparta.KeyReturn)
+	expectComboSel(t, c, 3)
+	m.Close()
+}
@@ -0,0 +1,481 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/js-arias/sparta"
+)
+
+// Dialog results.
+const (
+	DialogCancel = iota
+	DialogOK
+	DialogYes
+	DialogNo
+)
+
+// Dialog is a top level window, owned by other window (usually a main
+// window), that blocks the input to the other windows of the application
+// while it is open. Dialogs can be nested, i.e. a dialog can be opened from
+// other dialog.
+//
+// The dialog is closed with the Done method, that sets the result of the
+// dialog. By default, a command event from a button, or from an entry with
+// a value set with EntryValue, closes the dialog using the value of the
+// event as the result, the escape key (or closing the window) closes it with
+// DialogCancel, and the return key with the default result (see
+// DialogDefault), if it is set.
+//
+// When the dialog is done, and before its window is closed, the result is
+// passed to the function set with DialogFunc, or if there is no function,
+// a command event with the result is sent to the target of the dialog (by
+// default, the owner). The result, and the text of an input dialog, can be
+// read after the dialog is closed.
+type Dialog struct {
+	name       string
+	win        sparta.Window
+	owner      sparta.Widget
+	childs     []sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	windowState
+
+	title  string
+	target sparta.Widget
+	fn     func(*Dialog, int)
+	def    int
+	result int
+	done   bool
+	input  *Entry
+	text   string
+
+	layout anchorLayout
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// Dialog particular properties.
+const (
+	// sets the function (func(*Dialog, int)) that is called with the
+	// result of the dialog.
+	DialogFunc sparta.Property = "func"
+
+	// sets the result (int) used when the return key is pressed. If it
+	// is -1, the return key is not used.
+	DialogDefault = "default"
+
+	// result of the dialog (int). It is read only.
+	DialogResult = "result"
+
+	// text of an input dialog (string). It is read only.
+	DialogText = "text"
+)
+
+// NewDialog creates a new dialog. The position of the dialog is relative
+// to the position of its owner.
+func NewDialog(owner sparta.Widget, name, title string, rect image.Rectangle) *Dialog {
+	d := &Dialog{
+		name:     name,
+		owner:    owner,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		title:    title,
+		target:   owner,
+		def:      -1,
+		result:   DialogCancel,
+	}
+	if owner != nil {
+		d.geometry = rect.Add(sparta.RootPos(owner))
+	}
+	d.layout.setSize(nil, d.geometry.Size())
+	sparta.NewWindow(d)
+	d.win.SetProperty(sparta.Caption, d.title)
+	sparta.Block(d)
+	return d
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// dialog.
+func (d *Dialog) SetWindow(win sparta.Window) {
+	d.win = win
+}
+
+// Window returns the backend window.
+func (d *Dialog) Window() sparta.Window {
+	return d.win
+}
+
+// RemoveWindow removes the backend window.
+func (d *Dialog) RemoveWindow() {
+	d.win = nil
+	if !d.done {
+		d.done = true
+		sparta.Unblock(d)
+	}
+}
+
+// Property returns the indicated property of the dialog.
+func (d *Dialog) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Caption:
+		return d.title
+	case sparta.Childs:
+		return d.childs
+	case sparta.Data:
+		return d.data
+	case sparta.Geometry:
+		return d.geometry
+	case sparta.Name:
+		return d.name
+	case sparta.Foreground:
+		return d.fore
+	case sparta.Background:
+		return d.back
+	case sparta.Owner:
+		return d.owner
+	case sparta.Target:
+		return d.target
+	case DialogFunc:
+		return d.fn
+	case DialogDefault:
+		return d.def
+	case DialogResult:
+		return d.result
+	case DialogText:
+		if (d.input != nil) && !d.done {
+			return d.input.Property(EntryText)
+		}
+		return d.text
+	}
+	return d.property(p)
+}
+
+// SetProperty sets a property of the dialog.
+func (d *Dialog) SetProperty(p sparta.Property, v interface{}) {
+	if d.setProperty(d.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Caption:
+		val := v.(string)
+		if d.title != val {
+			d.title = val
+			d.win.SetProperty(sparta.Caption, d.title)
+		}
+	case sparta.Childs:
+		if v == nil {
+			d.childs = nil
+			return
+		}
+		d.childs = append(d.childs, v.(sparta.Widget))
+	case sparta.Data:
+		d.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !d.geometry.Eq(val) {
+			d.layout.setSize(d.childs, val.Size())
+			d.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Name:
+		val := v.(string)
+		if d.name != val {
+			d.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if d.fore != val {
+			d.fore = val
+			d.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if d.back != val {
+			d.back = val
+			d.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = d.owner
+		}
+		if d.target == val {
+			break
+		}
+		d.target = val
+	case DialogFunc:
+		if v == nil {
+			d.fn = nil
+			break
+		}
+		d.fn = v.(func(*Dialog, int))
+	case DialogDefault:
+		d.def = v.(int)
+	}
+}
+
+// Capture sets an event function of the dialog.
+func (d *Dialog) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		d.closeFn = fn
+	case sparta.Command:
+		d.commFn = fn
+	case sparta.Configure:
+		d.configFn = fn
+	case sparta.Expose:
+		d.exposeFn = fn
+	case sparta.KeyEv:
+		d.keyFn = fn
+	case sparta.Mouse:
+		d.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the dialog.
+func (d *Dialog) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(d) {
+				return
+			}
+		}
+		if d.closeFn != nil {
+			if d.closeFn(d, e) {
+				return
+			}
+		}
+		for _, c := range d.childs {
+			c.OnEvent(e)
+		}
+		d.Done(DialogCancel)
+	case sparta.CommandEvent:
+		ev := e.(sparta.CommandEvent)
+		if ev.Source == d {
+			d.Close()
+			return
+		}
+		if d.commFn != nil {
+			if d.commFn(d, e) {
+				return
+			}
+		}
+		switch src := ev.Source.(type) {
+		case *Button:
+			d.Done(ev.Value)
+		case *Entry:
+			if src.hasValue {
+				d.Done(ev.Value)
+			}
+		}
+	case sparta.ConfigureEvent:
+		d.geometry = e.(sparta.ConfigureEvent).Rect
+		d.layout.resize(d.childs, d.geometry.Size())
+		if d.configFn != nil {
+			d.configFn(d, e)
+		}
+	case sparta.ExposeEvent:
+		if d.exposeFn != nil {
+			d.exposeFn(d, e)
+		}
+	case sparta.KeyEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(d) {
+				return
+			}
+		}
+		if d.keyFn != nil {
+			if d.keyFn(d, e) {
+				return
+			}
+		}
+		ev := e.(sparta.KeyEvent)
+		switch ev.Key {
+		case sparta.KeyEscape:
+			d.Done(DialogCancel)
+		case sparta.KeyReturn, sparta.KeyPadEnter:
+			if d.def >= 0 {
+				d.Done(d.def)
+			}
+		}
+	case sparta.MouseEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(d) {
+				return
+			}
+		}
+		if d.mouseFn != nil {
+			d.mouseFn(d, e)
+		}
+	}
+}
+
+// Done closes the dialog with the given result.
+func (d *Dialog) Done(result int) {
+	if d.done {
+		return
+	}
+	d.done = true
+	d.result = result
+	if d.input != nil {
+		d.text = d.input.Property(EntryText).(string)
+	}
+	sparta.Unblock(d)
+	if (d.fn != nil) || (d.target == nil) {
+		if d.fn != nil {
+			d.fn(d, result)
+		}
+		d.Close()
+		return
+	}
+	sparta.SendEvent(d.target, sparta.CommandEvent{Source: d, Value: result})
+
+	// the window is closed after the target receives the result
+	sparta.SendEvent(d, sparta.CommandEvent{Source: d, Value: result})
+}
+
+// Update updates the dialog.
+func (d *Dialog) Update() {
+	d.win.Update()
+}
+
+// Focus set the focus on the dialog.
+func (d *Dialog) Focus() {
+	d.win.Focus()
+}
+
+// Close closes the dialog. If the dialog is not done, it is closed without
+// a result.
+func (d *Dialog) Close() {
+	if d.win == nil {
+		return
+	}
+	d.win.Close()
+}
+
+// dialog buttons
+type dialogButton struct {
+	caption string
+	value   int
+}
+
+// dialog sizes
+const (
+	dialogMargin = 10
+	dialogCols   = 50
+)
+
+// MessageBox shows a dialog with a message, and an OK button.
+func MessageBox(owner sparta.Widget, title, msg string) *Dialog {
+	return msgDialog(owner, "messageBox", title, msg, []dialogButton{
+		{"OK", DialogOK},
+	})
+}
+
+// Confirm shows a dialog with a question, and Yes, No and Cancel buttons.
+// The default result (when return is pressed) is DialogYes.
+func Confirm(owner sparta.Widget, title, msg string) *Dialog {
+	return msgDialog(owner, "confirm", title, msg, []dialogButton{
+		{"Yes", DialogYes},
+		{"No", DialogNo},
+		{"Cancel", DialogCancel},
+	})
+}
+
+// Input shows a dialog to edit a single line of text, with OK and Cancel
+// buttons. The text can be read with the property DialogText.
+func Input(owner sparta.Widget, title, prompt, text string) *Dialog {
+	bs := []dialogButton{
+		{"OK", DialogOK},
+		{"Cancel", DialogCancel},
+	}
+	lines := wrapText(strings.Split(prompt, "\n"), dialogCols)
+	w := dialogWidth(lines, bs)
+	if w < dialogCols*sparta.WidthUnit {
+		w = dialogCols * sparta.WidthUnit
+	}
+	y := dialogMargin + len(lines)*sparta.HeightUnit + 4
+	eh := sparta.HeightUnit + 6
+	h := y + dialogMargin + eh + dialogMargin + sparta.HeightUnit + 8 + dialogMargin
+	d := NewDialog(owner, "input", title, dialogRect(owner, w+2*dialogMargin, h))
+	d.def = DialogOK
+	NewLabel(d, "inputLabel", strings.Join(lines, "\n"), image.Rect(dialogMargin, dialogMargin, dialogMargin+w, y))
+	y += dialogMargin
+	d.input = NewEntry(d, "inputEntry", image.Rect(dialogMargin, y, dialogMargin+w, y+eh))
+	d.input.SetProperty(EntryText, text)
+	d.input.SetProperty(EntryValue, DialogOK)
+	dialogButtons(d, "input", bs, y+eh+dialogMargin, w+2*dialogMargin)
+	d.input.Focus()
+	return d
+}
+
+// MsgDialog creates a dialog with a message and a row of buttons. The
+// first button is the default.
+func msgDialog(owner sparta.Widget, name, title, msg string, bs []dialogButton) *Dialog {
+	lines := wrapText(strings.Split(msg, "\n"), dialogCols)
+	w := dialogWidth(lines, bs)
+	y := dialogMargin + len(lines)*sparta.HeightUnit + 4
+	h := y + dialogMargin + sparta.HeightUnit + 8 + dialogMargin
+	d := NewDialog(owner, name, title, dialogRect(owner, w+2*dialogMargin, h))
+	d.def = bs[0].value
+	NewLabel(d, name+"Label", strings.Join(lines, "\n"), image.Rect(dialogMargin, dialogMargin, dialogMargin+w, y))
+	dialogButtons(d, name, bs, y+dialogMargin, w+2*dialogMargin)
+	d.Focus()
+	return d
+}
+
+// DialogWidth returns the width of the content of a dialog.
+func dialogWidth(lines []string, bs []dialogButton) int {
+	w := 0
+	for _, ln := range lines {
+		if n := len([]rune(ln)); n > w {
+			w = n
+		}
+	}
+	w = w*sparta.WidthUnit + 4
+	if bw := len(bs)*(10*sparta.WidthUnit+dialogMargin) - dialogMargin; bw > w {
+		w = bw
+	}
+	return w
+}
+
+// DialogButtons adds a row of centered buttons to a dialog.
+func dialogButtons(d *Dialog, name string, bs []dialogButton, y, w int) {
+	bw := 10 * sparta.WidthUnit
+	x := (w - (len(bs)*(bw+dialogMargin) - dialogMargin)) / 2
+	for _, b := range bs {
+		btn := NewButton(d, name+b.caption, b.caption, image.Rect(x, y, x+bw, y+sparta.HeightUnit+8))
+		btn.SetProperty(ButtonValue, b.value)
+		x += bw + dialogMargin
+	}
+}
+
+// DialogRect returns the rectangle of a dialog of the given size, centered
+// over its owner.
+func dialogRect(owner sparta.Widget, w, h int) image.Rectangle {
+	if owner == nil {
+		return image.Rect(0, 0, w, h)
+	}
+	r := owner.Property(sparta.Geometry).(image.Rectangle)
+	x, y := (r.Dx()-w)/2, (r.Dy()-h)/2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return image.Rect(x, y, x+w, y+h)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/headless"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func TestDialogBlock(t *testing.T) {
+	m := widget.NewMainWindow("blockMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 400, 300))
+	b := widget.NewButton(m, "blockButton", "OK", image.Rect(10, 10, 80, 30))
+	e := widget.NewEntry(m, "blockEntry", image.Rect(10, 40, 150, 60))
+	tt := sparttest.New(t, m)
+	n := len(headless.Windows())
+	widget.MessageBox(m, "Info", "Hello")
+	tt.Idle()
+	if len(headless.Windows()) != n+1 {
+		t.Fatalf("message box not opened")
+	}
+	tt.Commands()
+	tt.Click("blockButton", sparta.MouseLeft, image.Pt(5, 5))
+	tt.Click("blockEntry", sparta.MouseLeft, image.Pt(5, 5))
+	tt.Type("blockEntry", 'a', sparta.KeyReturn)
+	for _, c := range tt.Commands() {
+		if c.Source == sparta.Widget(b) || c.Source == sparta.Widget(e) {
+			t.Errorf("command %+v from a blocked widget", c)
+		}
+	}
+	if s := e.Property(widget.EntryText).(string); s != "" {
+		t.Errorf("text %q typed in a blocked entry", s)
+	}
+	tt.Type("messageBoxOK", sparta.KeyReturn)
+	if len(headless.Windows()) != n || sparta.IsBlock() {
+		t.Fatalf("message box not closed")
+	}
+	tt.Click("blockButton", sparta.MouseLeft, image.Pt(5, 5))
+	tt.ExpectCommand("blockMain", "blockButton", 0)
+	m.Close()
+}
+
+func TestDialogConfirm(t *testing.T) {
+	m := widget.NewMainWindow("confirmMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 400, 300))
+	tt := sparttest.New(t, m)
+	n := len(headless.Windows())
+	d := widget.MessageBox(m, "Info", "Hello")
+	res := -1
+	c := widget.Confirm(d, "Question", "Sure?")
+	c.SetProperty(widget.DialogFunc, func(_ *widget.Dialog, r int) { res = r })
+	tt.Idle()
+
+	// the outer dialog is blocked by the nested one
+	tt.Type("messageBoxOK", sparta.KeyReturn)
+	if len(headless.Windows()) != n+2 {
+		t.Fatalf("outer dialog closed while the nested dialog is open")
+	}
+	tt.Click("confirmNo", sparta.MouseLeft, image.Pt(5, 5))
+	if res != widget.DialogNo {
+		t.Errorf("result %d, want %d", res, widget.DialogNo)
+	}
+	if r := c.Property(widget.DialogResult).(int); r != widget.DialogNo {
+		t.Errorf("result property %d, want %d", r, widget.DialogNo)
+	}
+	if len(headless.Windows()) != n+1 {
+		t.Fatalf("confirm dialog not closed")
+	}
+	tt.Type("messageBoxOK", sparta.KeyReturn)
+	if len(headless.Windows()) != n {
+		t.Fatalf("message box not closed")
+	}
+	m.Close()
+}
+
+func TestDialogInput(t *testing.T) {
+	m := widget.NewMainWindow("inputMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 400, 300))
+	tt := sparttest.New(t, m)
+	in := widget.Input(m, "Input", "Name:", "ab")
+	tt.Idle()
+	tt.Type("inputEntry", 'c', sparta.KeyReturn)
+	if s := in.Property(widget.DialogText).(string); s != "abc" {
+		t.Errorf("text %q, want %q", s, "abc")
+	}
+	if r := in.Property(widget.DialogResult).(int); r != widget.DialogOK {
+		t.Errorf("result %d, want %d", r, widget.DialogOK)
+	}
+	tt.ExpectCommand("inputMain", "input", widget.DialogOK)
+
+	widget.Input(m, "Input", "Name:", "ab")
+	tt.Idle()
+	tt.Type("inputEntry", sparta.KeyEscape)
+	tt.ExpectCommand("inputMain", "input", widget.DialogCancel)
+	if sparta.IsBlock() {
+		t.Errorf("input dialog not closed")
+	}
+	m.Close()
+}
+
+func TestDialogEntry(t *testing.T) {
+	m := widget.NewMainWindow("dialogEntryMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 400, 300))
+	tt := sparttest.New(t, m)
+	n := len(headless.Windows())
+	d := widget.NewDialog(m, "entryDialog", "Entry", image.Rect(0, 0, 200, 100))
+	widget.NewEntry(d, "dialogPlain", image.Rect(10, 10, 150, 30))
+	e := widget.NewEntry(d, "dialogValue", image.Rect(10, 40, 150, 60))
+	e.SetProperty(widget.EntryValue, widget.DialogCancel)
+	tt.Idle()
+
+	// an entry without a value does not close the dialog
+	tt.Type("dialogPlain", 'a', sparta.KeyReturn)
+	if len(headless.Windows()) != n+1 {
+		t.Fatalf("dialog closed by an entry without a value")
+	}
+
+	// a close event of a blocked window is not delivered
+	trace := headless.Trace
+	closed := false
+	headless.Trace = func(w sparta.Widget, e interface{}) {
+		if _, ok := e.(sparta.CloseEvent); ok && (w == m) {
+			closed = true
+		}
+		trace(w, e)
+	}
+	tt.Close("dialogEntryMain")
+	headless.Trace = trace
+	if closed || (m.Window() == nil) {
+		t.Fatalf("close event delivered to a blocked window")
+	}
+
+	tt.Type("dialogValue", sparta.KeyReturn)
+	if len(headless.Windows()) != n {
+		t.Fatalf("dialog not closed")
+	}
+	if r := d.Property(widget.DialogResult).(int); r != widget.DialogCancel {
+		t.Errorf("result %d, want %d", r, widget.DialogCancel)
+	}
+	m.Close()
+}
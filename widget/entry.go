@@ -0,0 +1,525 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+
+	"github.com/js-arias/sparta"
+)
+
+// Entry is a widget to edit a single line of text. The text can be
+// selected with the mouse, or with the arrow keys while the shift key is
+// pressed.
+//
+// When the Return key is pressed, it sends an arbitrary value (that can be
+// set with the property EntryValue) to the target widget.
+type Entry struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	widgetState
+
+	text      []rune
+	caret     int // caret position
+	anchor    int // start of the selection
+	offset    int // first visible character
+	max       int
+	password  bool
+	overwrite bool
+	target    sparta.Widget
+	value     int
+	hasValue  bool // the value was set with EntryValue
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// Entry particular properties.
+const (
+	// sets the text of the entry (string)
+	EntryText sparta.Property = "text"
+
+	// sets the position of the caret, in characters (int)
+	EntryCaret = "caret"
+
+	// sets the maximum length of the text (int). If 0, there is no
+	// maximum.
+	EntryMax = "max"
+
+	// sets the password mode (bool). In password mode, each character
+	// is shown as an asterisk.
+	EntryPassword = "password"
+
+	// sets the overwrite mode (bool). In overwrite mode, each new
+	// character replaces the character under the caret. It can be
+	// also switched with the Insert key.
+	EntryOverwrite = "overwrite"
+
+	// sets the value (int) that the entry will send to the target when
+	// Return is pressed.
+	EntryValue = "value"
+)
+
+// NewEntry creates a new text entry.
+func NewEntry(parent sparta.Widget, name string, rect image.Rectangle) *Entry {
+	e := &Entry{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		target:   parent,
+	}
+	sparta.NewWindow(e)
+	return e
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// entry.
+func (e *Entry) SetWindow(win sparta.Window) {
+	e.win = win
+}
+
+// Window returns the backend window.
+func (e *Entry) Window() sparta.Window {
+	return e.win
+}
+
+// RemoveWindow removes the backend window.
+func (e *Entry) RemoveWindow() {
+	e.win = nil
+}
+
+// Property returns the indicated property of the entry.
+func (e *Entry) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Data:
+		return e.data
+	case sparta.Geometry:
+		return e.geometry
+	case sparta.Parent:
+		return e.parent
+	case sparta.Name:
+		return e.name
+	case sparta.Foreground:
+		return e.fore
+	case sparta.Background:
+		return e.back
+	case sparta.Target:
+		return e.target
+	case EntryText:
+		return string(e.text)
+	case EntryCaret:
+		return e.caret
+	case EntryMax:
+		return e.max
+	case EntryPassword:
+		return e.password
+	case EntryOverwrite:
+		return e.overwrite
+	case EntryValue:
+		return e.value
+	case sparta.PrefSize:
+		return image.Pt(20*sparta.WidthUnit+6, sparta.HeightUnit+6)
+	}
+	return e.property(p)
+}
+
+// SetProperty sets a property of the entry.
+func (e *Entry) SetProperty(p sparta.Property, v interface{}) {
+	if e.setProperty(e.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Data:
+		e.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !e.geometry.Eq(val) {
+			e.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			e.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if e.name != val {
+			e.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if e.fore != val {
+			e.fore = val
+			e.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if e.back != val {
+			e.back = val
+			e.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = e.parent
+		}
+		if e.target == val {
+			break
+		}
+		e.target = val
+	case EntryText:
+		val := []rune(v.(string))
+		if (e.max > 0) && (len(val) > e.max) {
+			val = val[:e.max]
+		}
+		e.text = val
+		e.caret = len(e.text)
+		e.anchor = e.caret
+		e.offset = 0
+		e.showCaret()
+		e.Update()
+	case EntryCaret:
+		e.moveCaret(v.(int), false)
+	case EntryMax:
+		val := v.(int)
+		if val < 0 {
+			val = 0
+		}
+		if e.max == val {
+			break
+		}
+		e.max = val
+		if (e.max > 0) && (len(e.text) > e.max) {
+			e.text = e.text[:e.max]
+			e.moveCaret(e.caret, false)
+		}
+	case EntryPassword:
+		val := v.(bool)
+		if e.password != val {
+			e.password = val
+			e.Update()
+		}
+	case EntryOverwrite:
+		val := v.(bool)
+		if e.overwrite != val {
+			e.overwrite = val
+			e.Update()
+		}
+	case EntryValue:
+		val := v.(int)
+		e.hasValue = true
+		if e.value != val {
+			e.value = val
+		}
+	}
+}
+
+// Capture sets an event function of the entry.
+func (e *Entry) Capture(ev sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch ev {
+	case sparta.CloseEv:
+		e.closeFn = fn
+	case sparta.Configure:
+		e.configFn = fn
+	case sparta.Command:
+		e.commFn = fn
+	case sparta.Expose:
+		e.exposeFn = fn
+	case sparta.KeyEv:
+		e.keyFn = fn
+	case sparta.Mouse:
+		e.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the entry.
+func (e *Entry) OnEvent(ev interface{}) {
+	switch ev.(type) {
+	case sparta.CloseEvent:
+		if e.closeFn != nil {
+			e.closeFn(e, ev)
+		}
+	case sparta.ConfigureEvent:
+		e.geometry = ev.(sparta.ConfigureEvent).Rect
+		if e.configFn != nil {
+			e.configFn(e, ev)
+		}
+		e.showCaret()
+	case sparta.CommandEvent:
+		if e.commFn != nil {
+			if e.commFn(e, ev) {
+				return
+			}
+		}
+		e.parent.OnEvent(ev)
+	case sparta.ExposeEvent:
+		if e.exposeFn != nil {
+			e.exposeFn(e, ev)
+		}
+		e.expose()
+	case sparta.KeyEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(e) {
+				return
+			}
+		}
+		if e.keyFn != nil {
+			if e.keyFn(e, ev) {
+				return
+			}
+		}
+		e.key(ev.(sparta.KeyEvent))
+	case sparta.MouseEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(e) {
+				return
+			}
+		}
+		if e.mouseFn != nil {
+			if e.mouseFn(e, ev) {
+				return
+			}
+		}
+		mv := ev.(sparta.MouseEvent)
+		switch mv.Button {
+		case sparta.MouseLeft:
+			e.moveCaret(e.posAt(mv.Loc), (mv.State&sparta.StateShift) != 0)
+		case 0:
+			if (mv.State & sparta.StateButtonL) != 0 {
+				e.moveCaret(e.posAt(mv.Loc), true)
+			}
+		}
+	}
+}
+
+// Expose draws the entry.
+func (e *Entry) expose() {
+	e.win.SetColor(sparta.Foreground, foreColor)
+	y := (e.geometry.Dy() - sparta.HeightUnit) / 2
+	txt := e.text
+	if e.password {
+		txt = make([]rune, len(e.text))
+		for i := range txt {
+			txt[i] = '*'
+		}
+	}
+	end := e.offset + e.visible()
+	if end > len(txt) {
+		end = len(txt)
+	}
+	if e.offset < end {
+		s, t := e.selection()
+		selText(e.win, image.Pt(2, y), txt[e.offset:end], s-e.offset, t-e.offset, e.back)
+	}
+
+	// caret
+	x := 2 + ((e.caret - e.offset) * sparta.WidthUnit)
+	if e.overwrite {
+		e.win.Lines([]image.Point{image.Pt(x, y+sparta.HeightUnit-2), image.Pt(x+sparta.WidthUnit-1, y+sparta.HeightUnit-2)})
+	} else {
+		e.win.Lines([]image.Point{image.Pt(x-1, y), image.Pt(x-1, y+sparta.HeightUnit-2)})
+	}
+	rect := image.Rect(0, 0, e.geometry.Dx()-1, e.geometry.Dy()-1)
+	e.win.Rectangle(rect, false)
+}
+
+// SelText draws a text, in which the characters between s and t are shown
+// as selected.
+func selText(win sparta.Window, pt image.Point, txt []rune, s, t int, back color.RGBA) {
+	for i := 0; i < len(txt); {
+		j := len(txt)
+		sel := (i >= s) && (i < t)
+		if sel && (t < j) {
+			j = t
+		} else if !sel && (i < s) && (s < j) {
+			j = s
+		}
+		if sel {
+			win.SetColor(sparta.Foreground, back)
+			win.SetColor(sparta.Background, foreColor)
+		}
+		win.Text(image.Pt(pt.X+(i*sparta.WidthUnit), pt.Y), string(txt[i:j]))
+		if sel {
+			win.SetColor(sparta.Foreground, foreColor)
+			win.SetColor(sparta.Background, back)
+		}
+		i = j
+	}
+}
+
+// Key process a key press.
+func (e *Entry) key(ev sparta.KeyEvent) {
+	if ev.Key < 0 {
+		return
+	}
+	shift := (ev.State & sparta.StateShift) != 0
+	switch ev.Key {
+	case sparta.KeyLeft:
+		e.moveCaret(e.caret-1, shift)
+	case sparta.KeyRight:
+		e.moveCaret(e.caret+1, shift)
+	case sparta.KeyHome:
+		e.moveCaret(0, shift)
+	case sparta.KeyEnd:
+		e.moveCaret(len(e.text), shift)
+	case sparta.KeyBackSpace:
+		if !e.deleteSel() {
+			if e.caret == 0 {
+				break
+			}
+			e.text = append(e.text[:e.caret-1], e.text[e.caret:]...)
+			e.caret--
+			e.anchor = e.caret
+		}
+		e.showCaret()
+		e.Update()
+	case sparta.KeyDelete:
+		if !e.deleteSel() {
+			if e.caret == len(e.text) {
+				break
+			}
+			e.text = append(e.text[:e.caret], e.text[e.caret+1:]...)
+		}
+		e.showCaret()
+		e.Update()
+	case sparta.KeyInsert:
+		e.overwrite = !e.overwrite
+		e.Update()
+	case sparta.KeyReturn, sparta.KeyPadEnter:
+		sparta.SendEvent(e.target, sparta.CommandEvent{Source: e, Value: e.value})
+	default:
+		if ((ev.Key & sparta.KeyNoChar) != 0) || ((ev.State & (sparta.StateCtrl | sparta.StateAlt)) != 0) {
+			e.parent.OnEvent(ev)
+			return
+		}
+		r := rune(ev.Key)
+		if !unicode.IsPrint(r) {
+			e.parent.OnEvent(ev)
+			return
+		}
+		e.insert(r)
+	}
+}
+
+// Insert inserts a character in the caret position.
+func (e *Entry) insert(r rune) {
+	e.deleteSel()
+	if e.overwrite && (e.caret < len(e.text)) {
+		e.text[e.caret] = r
+	} else {
+		if (e.max > 0) && (len(e.text) >= e.max) {
+			return
+		}
+		e.text = append(e.text, 0)
+		copy(e.text[e.caret+1:], e.text[e.caret:])
+		e.text[e.caret] = r
+	}
+	e.caret++
+	e.anchor = e.caret
+	e.showCaret()
+	e.Update()
+}
+
+// Selection returns the start and end of the selection.
+func (e *Entry) selection() (int, int) {
+	if e.anchor < e.caret {
+		return e.anchor, e.caret
+	}
+	return e.caret, e.anchor
+}
+
+// DeleteSel deletes the selected text. It returns false if there is no
+// selection.
+func (e *Entry) deleteSel() bool {
+	s, t := e.selection()
+	if s == t {
+		return false
+	}
+	e.text = append(e.text[:s], e.text[t:]...)
+	e.caret = s
+	e.anchor = s
+	return true
+}
+
+// MoveCaret moves the caret to the indicated position. If sel is true,
+// the selection is extended to the new position.
+func (e *Entry) moveCaret(pos int, sel bool) {
+	if pos < 0 {
+		pos = 0
+	} else if pos > len(e.text) {
+		pos = len(e.text)
+	}
+	if !sel && (e.anchor != e.caret) {
+		e.anchor = e.caret
+		e.Update()
+	}
+	if e.caret == pos {
+		return
+	}
+	e.caret = pos
+	if !sel {
+		e.anchor = pos
+	}
+	e.showCaret()
+	e.Update()
+}
+
+// PosAt returns the position of the character at a given point.
+func (e *Entry) posAt(pt image.Point) int {
+	pos := e.offset + ((pt.X - 2 + (sparta.WidthUnit / 2)) / sparta.WidthUnit)
+	if pos < 0 {
+		return 0
+	}
+	if pos > len(e.text) {
+		return len(e.text)
+	}
+	return pos
+}
+
+// Visible returns the number of visible characters.
+func (e *Entry) visible() int {
+	n := (e.geometry.Dx() - 4) / sparta.WidthUnit
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// ShowCaret scrolls the text to make the caret visible.
+func (e *Entry) showCaret() {
+	n := e.visible()
+	if e.caret < e.offset {
+		e.offset = e.caret
+	} else if e.caret >= e.offset+n {
+		e.offset = e.caret - n + 1
+	}
+	if e.offset > 0 && (len(e.text)-e.offset) < n {
+		e.offset = len(e.text) - n + 1
+		if e.offset < 0 {
+			e.offset = 0
+		}
+	}
+}
+
+// Update updates the entry.
+func (e *Entry) Update() {
+	e.win.Update()
+}
+
+// Focus set the focus on the entry.
+func (e *Entry) Focus() {
+	e.win.Focus()
+}
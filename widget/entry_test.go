@@ -0,0 +1,71 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func expectText(t *testing.T, e *widget.Entry, want string) {
+	t.Helper()
+	if s := e.Property(widget.EntryText).(string); s != want {
+		t.Errorf("text %q, want %q", s, want)
+	}
+}
+
+func TestEntryEdit(t *testing.T) {
+	m := widget.NewMainWindow("editEntryMain", "test")
+	e := widget.NewEntry(m, "editEntry", image.Rect(10, 10, 70, 30))
+	e.SetProperty(widget.EntryValue, 5)
+	tt := sparttest.New(t, m)
+	tt.TypeString("editEntry", "hello world, long text")
+	expectText(t, e, "hello world, long text")
+
+	// replace a selection
+	tt.Type("editEntry", sparta.KeyHome, sparta.KeyRight)
+	tt.Key("editEntry", sparta.KeyEvent{Key: sparta.KeyRight, State: sparta.StateShift})
+	tt.Key("editEntry", sparta.KeyEvent{Key: sparta.KeyRight, State: sparta.StateShift})
+	tt.TypeString("editEntry", "X")
+	expectText(t, e, "hXlo world, long text")
+
+	// overwrite mode
+	tt.Type("editEntry", sparta.KeyEnd, sparta.KeyBackSpace, sparta.KeyHome, sparta.KeyDelete, sparta.KeyInsert)
+	tt.TypeString("editEntry", "AB")
+	expectText(t, e, "ABo world, long tex")
+
+	tt.Commands()
+	tt.Type("editEntry", sparta.KeyReturn)
+	tt.ExpectCommand("editEntryMain", "editEntry", 5)
+	m.Close()
+}
+
+func TestEntryMax(t *testing.T) {
+	m := widget.NewMainWindow("maxEntryMain", "test")
+	e := widget.NewEntry(m, "maxEntry", image.Rect(10, 10, 70, 30))
+	e.SetProperty(widget.EntryMax, 3)
+	e.SetProperty(widget.EntryPassword, true)
+	tt := sparttest.New(t, m)
+	tt.TypeString("maxEntry", "abcd")
+	expectText(t, e, "abc")
+	m.Close()
+}
+
+func TestEntryDisabled(t *testing.T) {
+	m := widget.NewMainWindow("disEntryMain", "test")
+	e := widget.NewEntry(m, "disEntry", image.Rect(10, 10, 70, 30))
+	e.SetProperty(sparta.Enabled, false)
+	tt := sparttest.New(t, m)
+	tt.TypeString("disEntry", "abc")
+	expectText(t, e, "")
+	e.SetProperty(sparta.Enabled, true)
+	tt.TypeString("disEntry", "abc")
+	expectText(t, e, "abc")
+	m.Close()
+}
@@ -0,0 +1,17 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import "time"
+
+// SetListNow sets the clock used by the lists, and returns a function
+// that restores the previous clock.
+func SetListNow(now func() time.Time) func() {
+	prev := listNow
+	listNow = now
+	return func() {
+		listNow = prev
+	}
+}
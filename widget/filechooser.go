@@ -0,0 +1,366 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/js-arias/sparta"
+)
+
+// OpenFile shows a file chooser dialog to select an existing file. The
+// dialog starts at the directory dir (or the working directory, if dir is
+// empty), and only shows the files that match the first of the glob
+// filters (for example "*.tnt"). The filter can be changed in the dialog,
+// and a filter that shows all the files is always added.
+//
+// A directory is opened by clicking on it, and a file is selected by
+// clicking on it. A second click on the selected file chooses it. If a
+// glob pattern is typed as the file name, it is used as the filter.
+//
+// The path of the chosen file can be read with the property DialogText.
+func OpenFile(owner sparta.Widget, title, dir string, filters []string) *Dialog {
+	return newFileChooser(owner, title, dir, "", filters, false)
+}
+
+// SaveFile shows a file chooser dialog to select the name of a file to be
+// saved, using file as the initial name. If the chosen file already
+// exists, the dialog asks for confirmation before replacing it. The other
+// options are the same as in OpenFile.
+func SaveFile(owner sparta.Widget, title, dir, file string, filters []string) *Dialog {
+	return newFileChooser(owner, title, dir, file, filters, true)
+}
+
+// fileChooser keeps the state of a file chooser dialog. It is also the
+// data of the file list.
+type fileChooser struct {
+	d       *Dialog
+	save    bool
+	dir     string
+	files   []os.FileInfo
+	sel     int
+	filters stringList
+	hidden  bool
+
+	path   *Label
+	up     *Button
+	list   *List
+	file   *Entry
+	filter *ComboBox
+	hide   *CheckBox
+	ok     *Button
+}
+
+// file chooser sizes
+const (
+	fileChooserWidth = 440
+	fileChooserRows  = 12
+)
+
+// NewFileChooser creates a file chooser dialog.
+func newFileChooser(owner sparta.Widget, title, dir, file string, filters []string, save bool) *Dialog {
+	fc := &fileChooser{
+		save: save,
+		sel:  -1,
+	}
+	fc.filters.items = append(fc.filters.items, filters...)
+	if (len(filters) == 0) || (filters[len(filters)-1] != "*") {
+		fc.filters.items = append(fc.filters.items, "*")
+	}
+
+	h := sparta.HeightUnit
+	w := fileChooserWidth - 2*dialogMargin
+	bw := 10 * sparta.WidthUnit
+	lw := 11 * sparta.WidthUnit
+	y := dialogMargin
+	ly := y + h + 8 + 4
+	fy := ly + fileChooserRows*h + 4 + dialogMargin
+	cy := fy + h + 6 + 4
+	by := cy + h + 6 + dialogMargin
+	height := by + h + 8 + dialogMargin
+
+	fc.d = NewDialog(owner, "fileChooser", title, dialogRect(owner, fileChooserWidth, height))
+	fc.d.Capture(sparta.Command, fc.command)
+
+	fc.path = NewLabel(fc.d, "fileChooserPath", "", image.Rect(dialogMargin, y, dialogMargin+w-bw-dialogMargin, y+h+8))
+	fc.path.SetProperty(LabelAlign, AlignLeft)
+	fc.up = NewButton(fc.d, "fileChooserUp", "Up", image.Rect(dialogMargin+w-bw, y, dialogMargin+w, y+h+8))
+	fc.list = NewList(fc.d, "fileChooserList", image.Rect(dialogMargin, ly, dialogMargin+w, ly+fileChooserRows*h+4))
+
+	l := NewLabel(fc.d, "fileChooserNameLabel", "File name:", image.Rect(dialogMargin, fy, dialogMargin+lw, fy+h+6))
+	l.SetProperty(LabelAlign, AlignLeft)
+	fc.file = NewEntry(fc.d, "fileChooserName", image.Rect(dialogMargin+lw, fy, dialogMargin+w, fy+h+6))
+	fc.file.SetProperty(EntryText, file)
+
+	l = NewLabel(fc.d, "fileChooserFilterLabel", "Filter:", image.Rect(dialogMargin, cy, dialogMargin+lw, cy+h+6))
+	l.SetProperty(LabelAlign, AlignLeft)
+	fc.filter = NewComboBox(fc.d, "fileChooserFilter", image.Rect(dialogMargin+lw, cy, dialogMargin+lw+20*sparta.WidthUnit, cy+h+6))
+	fc.filter.SetProperty(ComboList, &fc.filters)
+	fc.hide = NewCheckBox(fc.d, "fileChooserHidden", "Show hidden files", image.Rect(dialogMargin+w-22*sparta.WidthUnit, cy, dialogMargin+w, cy+h+6))
+
+	caption := "Open"
+	if save {
+		caption = "Save"
+	}
+	fc.ok = NewButton(fc.d, "fileChooser"+caption, caption, image.Rect(dialogMargin+w-2*bw-dialogMargin, by, dialogMargin+w-bw-dialogMargin, by+h+8))
+	cancel := NewButton(fc.d, "fileChooserCancel", "Cancel", image.Rect(dialogMargin+w-bw, by, dialogMargin+w, by+h+8))
+	cancel.SetProperty(ButtonValue, DialogCancel)
+
+	if dir == "" {
+		dir = "."
+	}
+	if !fc.setDir(dir, false) {
+		wd, _ := os.Getwd()
+		fc.setDir(wd, true)
+	}
+	fc.file.Focus()
+	return fc.d
+}
+
+// Command process the command events of the file chooser dialog.
+func (fc *fileChooser) command(w sparta.Widget, e interface{}) bool {
+	ev := e.(sparta.CommandEvent)
+	switch ev.Source {
+	case fc.up:
+		fc.setDir(filepath.Dir(fc.dir), true)
+	case fc.list:
+		fc.click(ev.Value)
+	case fc.file, fc.ok:
+		fc.accept()
+	case fc.filter:
+		fc.setDir(fc.dir, true)
+	case fc.hide:
+		fc.hidden = fc.hide.Property(CheckBoxState).(CheckState) == Checked
+		fc.setDir(fc.dir, true)
+	default:
+		return false
+	}
+	return true
+}
+
+// Click process a click on the i-th element of the file list.
+func (fc *fileChooser) click(i int) {
+	if (i < 0) || (i >= len(fc.files)) {
+		return
+	}
+	fi := fc.files[i]
+	if fi.IsDir() {
+		fc.setDir(filepath.Join(fc.dir, fi.Name()), true)
+		return
+	}
+	if i == fc.sel {
+		fc.accept()
+		return
+	}
+	fc.sel = i
+	fc.file.SetProperty(EntryText, fi.Name())
+	fc.list.Update()
+}
+
+// Accept process the file name of the entry. If it is a directory, the
+// directory is opened, if it is a glob pattern, it is used as the filter,
+// otherwise the file is chosen.
+func (fc *fileChooser) accept() {
+	name := strings.TrimSpace(fc.file.Property(EntryText).(string))
+	if len(name) == 0 {
+		return
+	}
+	if strings.ContainsAny(name, "*?[") {
+		if _, err := filepath.Match(name, ""); err != nil {
+			MessageBox(fc.d, fc.d.title, err.Error())
+			return
+		}
+		fc.file.SetProperty(EntryText, "")
+		fc.filters.items = append(fc.filters.items, name)
+		fc.filter.SetProperty(ComboList, &fc.filters)
+		fc.filter.SetProperty(ComboSel, len(fc.filters.items)-1)
+		fc.setDir(fc.dir, true)
+		return
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(fc.dir, path)
+	}
+	fi, err := os.Stat(path)
+	if (err == nil) && fi.IsDir() {
+		fc.file.SetProperty(EntryText, "")
+		fc.setDir(path, true)
+		return
+	}
+	if !fc.save {
+		if err != nil {
+			MessageBox(fc.d, fc.d.title, fmt.Sprintf("File %s not found.", name))
+			return
+		}
+		fc.done(path)
+		return
+	}
+	if err == nil {
+		c := Confirm(fc.d, fc.d.title, fmt.Sprintf("%s already exists.\nDo you want to replace it?", filepath.Base(path)))
+		c.SetProperty(DialogFunc, func(_ *Dialog, r int) {
+			if r == DialogYes {
+				fc.done(path)
+			}
+		})
+		return
+	}
+	if _, err := os.Stat(filepath.Dir(path)); err != nil {
+		MessageBox(fc.d, fc.d.title, fmt.Sprintf("Directory %s not found.", filepath.Dir(path)))
+		return
+	}
+	fc.done(path)
+}
+
+// Done closes the dialog with the chosen path.
+func (fc *fileChooser) done(path string) {
+	fc.d.text = path
+	fc.d.Done(DialogOK)
+}
+
+// SetDir reads the content of a directory, and shows it in the file
+// list. If the directory can not be read, it returns false, and if report
+// is true, the error is shown in a message box.
+func (fc *fileChooser) setDir(dir string, report bool) bool {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	files, err := fc.readDir(dir)
+	if err != nil {
+		if report {
+			MessageBox(fc.d, fc.d.title, err.Error())
+		}
+		return false
+	}
+	fc.dir = dir
+	fc.files = files
+	fc.sel = -1
+
+	// shows the end of the path, if it is too long
+	path := []rune(dir)
+	if cols := fc.path.geometry.Dx()/sparta.WidthUnit - 1; len(path) > cols {
+		path = append([]rune("..."), path[len(path)-cols+3:]...)
+	}
+	fc.path.SetProperty(sparta.Caption, string(path))
+	fc.list.SetProperty(ListList, fc)
+	return true
+}
+
+// ReadDir returns the directories and the files, that match the current
+// filter, of a directory. Directories are listed first.
+func (fc *fileChooser) readDir(dir string) ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	filter := "*"
+	if sel := fc.filter.Property(ComboSel).(int); sel >= 0 {
+		filter = fc.filters.items[sel]
+	}
+	var dirs, files []os.FileInfo
+	for _, de := range entries {
+		if !fc.hidden && strings.HasPrefix(de.Name(), ".") {
+			continue
+		}
+		fi, err := de.Info()
+		if err != nil {
+			// the file was removed after the directory was read
+			continue
+		}
+		if (fi.Mode() & os.ModeSymlink) != 0 {
+			if st, err := os.Stat(filepath.Join(dir, fi.Name())); err == nil {
+				fi = namedInfo{st, fi.Name()}
+			}
+		}
+		if fi.IsDir() {
+			dirs = append(dirs, fi)
+			continue
+		}
+		if ok, _ := filepath.Match(filter, fi.Name()); !ok {
+			continue
+		}
+		files = append(files, fi)
+	}
+	return append(dirs, files...), nil
+}
+
+// Len returns the number of elements of the file list.
+func (fc *fileChooser) Len() int {
+	return len(fc.files)
+}
+
+// Item returns the description of the i-th element of the file list.
+func (fc *fileChooser) Item(i int) string {
+	fi := fc.files[i]
+	if fi.IsDir() {
+		return fi.Name() + string(filepath.Separator)
+	}
+	date := fi.ModTime().Format("2006-01-02 15:04")
+	n := (fc.list.geometry.Dx()-12)/sparta.WidthUnit - 2 - len(date) - 11
+	if n < 1 {
+		n = 1
+	}
+	name := []rune(fi.Name())
+	if len(name) > n {
+		name = append(name[:n-1], '~')
+	}
+	return fmt.Sprintf("%-*s %9s %s", n, string(name), fileSize(fi.Size()), date)
+}
+
+// IsSel returns true if the i-th element of the file list is selected.
+func (fc *fileChooser) IsSel(i int) bool {
+	return i == fc.sel
+}
+
+// namedInfo is the information of a file, accessed through a symbolic
+// link.
+type namedInfo struct {
+	os.FileInfo
+	name string
+}
+
+// Name returns the name of the link.
+func (fi namedInfo) Name() string {
+	return fi.name
+}
+
+// FileSize returns a file size in a human readable format.
+func fileSize(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	units := "KMGT"
+	v := float64(n) / 1024
+	u := 0
+	for (v >= 1024) && (u < len(units)-1) {
+		v /= 1024
+		u++
+	}
+	return fmt.Sprintf("%.1f %cB", v, units[u])
+}
+
+// stringList is a list of strings, in which the first element is
+// selected.
+type stringList struct {
+	items []string
+}
+
+// Len returns the length of the list.
+func (l *stringList) Len() int {
+	return len(l.items)
+}
+
+// Item returns the i-th element of the list.
+func (l *stringList) Item(i int) string {
+	return l.items[i]
+}
+
+// IsSel returns true for the first element.
+func (l *stringList) IsSel(i int) bool {
+	return i == 0
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/headless"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+// FileNames returns the names shown in the list of a file chooser.
+func fileNames(tt *sparttest.Tester) []string {
+	l := tt.Widget("fileChooserList").Property(widget.ListList).(widget.ListData)
+	var names []string
+	for i := 0; i < l.Len(); i++ {
+		names = append(names, strings.Fields(l.Item(i))[0])
+	}
+	return names
+}
+
+func expectFiles(t *testing.T, tt *sparttest.Tester, want ...string) {
+	t.Helper()
+	if got := fileNames(tt); !reflect.DeepEqual(got, want) {
+		t.Errorf("files %q, want %q", got, want)
+	}
+}
+
+func TestFileChooser(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"sub", ".hid"} {
+		if err := os.Mkdir(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"a.txt", "b.go", ".c.txt", filepath.Join("sub", "d.txt")} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sep := string(filepath.Separator)
+
+	m := widget.NewMainWindow("fileMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 400, 300))
+	tt := sparttest.New(t, m)
+	n := len(headless.Windows())
+	d := widget.OpenFile(m, "Open", dir, []string{"*.txt"})
+	tt.Idle()
+
+	// directories are listed first, and the files are filtered
+	expectFiles(t, tt, "sub"+sep, "a.txt")
+
+	tt.Click("fileChooserHidden", sparta.MouseLeft, image.Pt(5, 5))
+	expectFiles(t, tt, ".hid"+sep, "sub"+sep, ".c.txt", "a.txt")
+	tt.Click("fileChooserHidden", sparta.MouseLeft, image.Pt(5, 5))
+	expectFiles(t, tt, "sub"+sep, "a.txt")
+
+	// a click on a directory opens it
+	tt.Click("fileChooserList", sparta.MouseLeft, rowPt(0))
+	expectFiles(t, tt, "d.txt")
+	tt.Click("fileChooserUp", sparta.MouseLeft, image.Pt(5, 5))
+	expectFiles(t, tt, "sub"+sep, "a.txt")
+
+	// a glob pattern is used as the filter
+	tt.TypeString("fileChooserName", "*.go")
+	tt.Type("fileChooserName", sparta.KeyReturn)
+	expectFiles(t, tt, "sub"+sep, "b.go")
+	if s := tt.Widget("fileChooserName").Property(widget.EntryText).(string); s != "" {
+		t.Errorf("file name %q after a filter, want empty", s)
+	}
+
+	// a click on a file sets the file name
+	tt.Click("fileChooserList", sparta.MouseLeft, rowPt(1))
+	if s := tt.Widget("fileChooserName").Property(widget.EntryText).(string); s != "b.go" {
+		t.Errorf("file name %q, want %q", s, "b.go")
+	}
+
+	// names are truncated in a narrow list
+	tt.Configure("fileChooserList", image.Rect(0, 0, 30, 100))
+	l := tt.Widget("fileChooserList").Property(widget.ListList).(widget.ListData)
+	if s := l.Item(1); !strings.HasPrefix(s, "~") {
+		t.Errorf("item %q in a narrow list", s)
+	}
+
+	tt.Type("fileChooserName", sparta.KeyReturn)
+	if len(headless.Windows()) != n {
+		t.Fatalf("file chooser not closed")
+	}
+	if p := d.Property(widget.DialogText).(string); p != filepath.Join(dir, "b.go") {
+		t.Errorf("path %q, want %q", p, filepath.Join(dir, "b.go"))
+	}
+	tt.ExpectCommand("fileMain", "fileChooser", widget.DialogOK)
+	m.Close()
+}
@@ -0,0 +1,525 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/js-arias/sparta"
+)
+
+// AlignFill is the alignment of a widget that fills the space available
+// for it, in layout containers.
+const AlignFill Alignment = 3
+
+// Grid is a container that lays out its children in the cells of a grid.
+// A child can span several rows and columns. The children are laid out
+// each time the grid is resized. Only the children placed with the Place
+// method are laid out.
+//
+// Each column takes the width of its widest child (using its preferred
+// size, see sparta.PrefSize, or the size with which the child was
+// created, if it does not have a preferred size), and each row the height
+// of its tallest child. The space left (or missing) is shared between the
+// rows and columns with a weight, in proportion to the weight.
+//
+// By default, a child fills its cell, other alignments can be set with
+// the SetAlign method.
+type Grid struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	childs     []sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	border     bool
+	data       interface{}
+	widgetState
+
+	hgap, vgap int
+	padding    int
+	cells      map[sparta.Widget]*gridCell
+	rowWeight  map[int]int
+	colWeight  map[int]int
+	pending    bool
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// gridCell is the cell of a child of a grid.
+type gridCell struct {
+	placed         bool
+	row, col       int
+	rows, cols     int
+	halign, valign Alignment
+	pref           image.Point
+}
+
+// Grid particular properties.
+const (
+	// sets the space between the columns of the grid (int).
+	GridHGap sparta.Property = "hgap"
+
+	// sets the space between the rows of the grid (int).
+	GridVGap = "vgap"
+
+	// sets the space between the border of the grid and its children
+	// (int).
+	GridPadding = "padding"
+)
+
+// NewGrid creates a new grid.
+func NewGrid(parent sparta.Widget, name string, rect image.Rectangle) *Grid {
+	g := &Grid{
+		name:      name,
+		parent:    parent,
+		geometry:  rect,
+		back:      backColor,
+		fore:      foreColor,
+		cells:     make(map[sparta.Widget]*gridCell),
+		rowWeight: make(map[int]int),
+		colWeight: make(map[int]int),
+	}
+	sparta.NewWindow(g)
+	return g
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// grid.
+func (g *Grid) SetWindow(win sparta.Window) {
+	g.win = win
+}
+
+// Window returns the backend window.
+func (g *Grid) Window() sparta.Window {
+	return g.win
+}
+
+// RemoveWindow removes the backend window.
+func (g *Grid) RemoveWindow() {
+	g.win = nil
+}
+
+// Property returns the indicated property of the grid.
+func (g *Grid) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Childs:
+		return g.childs
+	case sparta.Data:
+		return g.data
+	case sparta.Geometry:
+		return g.geometry
+	case sparta.Parent:
+		return g.parent
+	case sparta.Name:
+		return g.name
+	case sparta.Foreground:
+		return g.fore
+	case sparta.Background:
+		return g.back
+	case sparta.Border:
+		return g.border
+	case sparta.PrefSize:
+		ws := g.placed()
+		cols, rows := g.tracks(ws, false), g.tracks(ws, true)
+		return image.Pt(trackSize(cols, g.hgap)+2*g.padding, trackSize(rows, g.vgap)+2*g.padding)
+	case GridHGap:
+		return g.hgap
+	case GridVGap:
+		return g.vgap
+	case GridPadding:
+		return g.padding
+	}
+	return g.property(p)
+}
+
+// SetProperty sets a property of the grid.
+func (g *Grid) SetProperty(p sparta.Property, v interface{}) {
+	if g.setProperty(g.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Childs:
+		if v == nil {
+			g.childs = nil
+			return
+		}
+		w := v.(sparta.Widget)
+		g.childs = append(g.childs, w)
+		c := g.cell(w)
+
+		// without a preferred size, the initial size is used
+		if w.Property(sparta.PrefSize) == nil {
+			c.pref = w.Property(sparta.Geometry).(image.Rectangle).Size()
+		}
+	case sparta.Data:
+		g.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !g.geometry.Eq(val) {
+			g.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			g.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if g.name != val {
+			g.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if g.fore != val {
+			g.fore = val
+			g.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if g.back != val {
+			g.back = val
+			g.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Border:
+		val := v.(bool)
+		if g.border != val {
+			g.border = val
+			g.Update()
+		}
+	case GridHGap:
+		g.hgap = v.(int)
+		g.relayout()
+	case GridVGap:
+		g.vgap = v.(int)
+		g.relayout()
+	case GridPadding:
+		g.padding = v.(int)
+		g.relayout()
+	}
+}
+
+// Capture sets an event function of the grid.
+func (g *Grid) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		g.closeFn = fn
+	case sparta.Configure:
+		g.configFn = fn
+	case sparta.Command:
+		g.commFn = fn
+	case sparta.Expose:
+		g.exposeFn = fn
+	case sparta.KeyEv:
+		g.keyFn = fn
+	case sparta.Mouse:
+		g.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the grid.
+func (g *Grid) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if g.closeFn != nil {
+			g.closeFn(g, e)
+		}
+		for _, ch := range g.childs {
+			ch.OnEvent(e)
+		}
+	case sparta.ConfigureEvent:
+		g.geometry = e.(sparta.ConfigureEvent).Rect
+		if g.configFn != nil {
+			g.configFn(g, e)
+		}
+		g.Layout()
+	case sparta.CommandEvent:
+		ev := e.(sparta.CommandEvent)
+		if ev.Source == g {
+			if g.pending {
+				g.Layout()
+			}
+			return
+		}
+		if g.commFn != nil {
+			if g.commFn(g, e) {
+				return
+			}
+		}
+		g.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if g.exposeFn != nil {
+			g.exposeFn(g, e)
+		}
+		if g.border {
+			g.win.SetColor(sparta.Foreground, foreColor)
+			rect := image.Rect(0, 0, g.geometry.Dx()-1, g.geometry.Dy()-1)
+			g.win.Rectangle(rect, false)
+		}
+	case sparta.KeyEvent:
+		if g.keyFn != nil {
+			if g.keyFn(g, e) {
+				return
+			}
+		}
+		g.parent.OnEvent(e)
+	case sparta.MouseEvent:
+		if g.mouseFn != nil {
+			if g.mouseFn(g, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		ev.Loc = ev.Loc.Add(g.geometry.Min)
+		g.parent.OnEvent(ev)
+	}
+}
+
+// Update updates the grid.
+func (g *Grid) Update() {
+	g.win.Update()
+}
+
+// Focus set the focus on the grid.
+func (g *Grid) Focus() {
+	g.win.Focus()
+}
+
+// Place places a child of the grid in the cell at the given row and
+// column (starting from 0), spanning the given number of rows and
+// columns.
+func (g *Grid) Place(w sparta.Widget, row, col, rows, cols int) {
+	if rows < 1 {
+		rows = 1
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	c := g.cell(w)
+	c.placed = true
+	c.row, c.col = row, col
+	c.rows, c.cols = rows, cols
+	g.relayout()
+}
+
+// SetAlign sets the horizontal (AlignLeft, AlignCenter, AlignRight or
+// AlignFill) and vertical (AlignTop, AlignMiddle, AlignBottom or AlignFill)
+// alignment of a child inside its cell.
+func (g *Grid) SetAlign(w sparta.Widget, h, v Alignment) {
+	c := g.cell(w)
+	c.halign, c.valign = h, v
+	g.relayout()
+}
+
+// SetRowWeight sets the weight of a row of the grid. By default the weight
+// is 0, so the row keeps the height of its tallest child.
+func (g *Grid) SetRowWeight(row, weight int) {
+	g.rowWeight[row] = weight
+	g.relayout()
+}
+
+// SetColWeight sets the weight of a column of the grid. By default the
+// weight is 0, so the column keeps the width of its widest child.
+func (g *Grid) SetColWeight(col, weight int) {
+	g.colWeight[col] = weight
+	g.relayout()
+}
+
+// Cell returns the cell of a child.
+func (g *Grid) cell(w sparta.Widget) *gridCell {
+	c, ok := g.cells[w]
+	if !ok {
+		c = &gridCell{
+			rows:   1,
+			cols:   1,
+			halign: AlignFill,
+			valign: AlignFill,
+		}
+		g.cells[w] = c
+	}
+	return c
+}
+
+// Placed returns the children of the grid that are placed in a cell.
+func (g *Grid) placed() []sparta.Widget {
+	var ws []sparta.Widget
+	for _, w := range g.childs {
+		if (w.Window() != nil) && g.cell(w).placed {
+			ws = append(ws, w)
+		}
+	}
+	return ws
+}
+
+// PrefSize returns the preferred size of a child.
+func (g *Grid) prefSize(w sparta.Widget) image.Point {
+	if c := g.cell(w); !c.pref.Eq(image.ZP) {
+		return c.pref
+	}
+	return prefSize(w)
+}
+
+// Relayout lays out the children of the grid, after all the pending
+// events are processed.
+func (g *Grid) relayout() {
+	if g.pending || (g.win == nil) {
+		return
+	}
+	g.pending = true
+	sparta.SendEvent(g, sparta.CommandEvent{Source: g})
+}
+
+// Layout sets the geometry of the children of the grid.
+func (g *Grid) Layout() {
+	g.pending = false
+	ws := g.placed()
+	if len(ws) == 0 {
+		return
+	}
+	cols, rows := g.tracks(ws, false), g.tracks(ws, true)
+	fitTracks(cols, g.colWeight, g.geometry.Dx()-2*g.padding-trackSize(cols, g.hgap))
+	fitTracks(rows, g.rowWeight, g.geometry.Dy()-2*g.padding-trackSize(rows, g.vgap))
+	xs := trackPos(cols, g.padding, g.hgap)
+	ys := trackPos(rows, g.padding, g.vgap)
+	for _, w := range ws {
+		c := g.cell(w)
+		cell := image.Rect(xs[c.col], ys[c.row], xs[c.col+c.cols]-g.hgap, ys[c.row+c.rows]-g.vgap)
+		w.SetProperty(sparta.Geometry, alignRect(cell, g.prefSize(w), c.halign, c.valign))
+	}
+}
+
+// Tracks returns the preferred size of the columns (or the rows) of the
+// grid.
+func (g *Grid) tracks(ws []sparta.Widget, rows bool) []int {
+	gap := g.hgap
+	if rows {
+		gap = g.vgap
+	}
+	n := 0
+	span := func(c *gridCell) (int, int) {
+		if rows {
+			return c.row, c.rows
+		}
+		return c.col, c.cols
+	}
+	for _, w := range ws {
+		if i, s := span(g.cell(w)); i+s > n {
+			n = i + s
+		}
+	}
+	sizes := make([]int, n)
+
+	// first the children in a single track, then the spanning children
+	for _, single := range []bool{true, false} {
+		for _, w := range ws {
+			i, s := span(g.cell(w))
+			if (s == 1) != single {
+				continue
+			}
+			need := g.prefSize(w).X
+			if rows {
+				need = g.prefSize(w).Y
+			}
+			have := trackSize(sizes[i:i+s], gap)
+			if need <= have {
+				continue
+			}
+			d := need - have
+			for k := 0; k < s; k++ {
+				sizes[i+k] += d / s
+				if k < d%s {
+					sizes[i+k]++
+				}
+			}
+		}
+	}
+	return sizes
+}
+
+// FitTracks shares the space left (or missing) between the tracks, in
+// proportion to its weight.
+func fitTracks(sizes []int, weights map[int]int, extra int) {
+	total := 0
+	for i := range sizes {
+		total += weights[i]
+	}
+	if (total == 0) || (extra == 0) {
+		return
+	}
+	rest, last := extra, 0
+	for i := range sizes {
+		wt := weights[i]
+		if wt <= 0 {
+			continue
+		}
+		d := (extra * wt) / total
+		sizes[i] += d
+		rest -= d
+		last = i
+	}
+	sizes[last] += rest
+	for i := range sizes {
+		if sizes[i] < 0 {
+			sizes[i] = 0
+		}
+	}
+}
+
+// TrackSize returns the total size of a set of tracks.
+func trackSize(sizes []int, gap int) int {
+	if len(sizes) == 0 {
+		return 0
+	}
+	t := (len(sizes) - 1) * gap
+	for _, s := range sizes {
+		t += s
+	}
+	return t
+}
+
+// TrackPos returns the start position of each track, and the end of the
+// last track (plus a gap).
+func trackPos(sizes []int, start, gap int) []int {
+	pos := make([]int, len(sizes)+1)
+	pos[0] = start
+	for i, s := range sizes {
+		pos[i+1] = pos[i] + s + gap
+	}
+	return pos
+}
+
+// AlignRect returns the rectangle of a widget, with the given size,
+// aligned inside a cell.
+func alignRect(cell image.Rectangle, size image.Point, h, v Alignment) image.Rectangle {
+	r := cell
+	if (h != AlignFill) && (size.X < cell.Dx()) {
+		switch h {
+		case AlignLeft:
+			r.Max.X = r.Min.X + size.X
+		case AlignCenter:
+			r.Min.X += (cell.Dx() - size.X) / 2
+			r.Max.X = r.Min.X + size.X
+		case AlignRight:
+			r.Min.X = r.Max.X - size.X
+		}
+	}
+	if (v != AlignFill) && (size.Y < cell.Dy()) {
+		switch v {
+		case AlignTop:
+			r.Max.Y = r.Min.Y + size.Y
+		case AlignMiddle:
+			r.Min.Y += (cell.Dy() - size.Y) / 2
+			r.Max.Y = r.Min.Y + size.Y
+		case AlignBottom:
+			r.Min.Y = r.Max.Y - size.Y
+		}
+	}
+	return r
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func TestGrid(t *testing.T) {
+	m := widget.NewMainWindow("gridMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 300, 200))
+	g := widget.NewGrid(m, "grid", image.Rect(0, 0, 200, 100))
+	g.SetProperty(widget.GridPadding, 5)
+	g.SetProperty(widget.GridHGap, 10)
+	g.SetProperty(widget.GridVGap, 4)
+	a := widget.NewCanvas(g, "gridA", image.Rect(0, 0, 20, 10))
+	b := widget.NewCanvas(g, "gridB", image.Rect(0, 0, 30, 20))
+	c := widget.NewCanvas(g, "gridC", image.Rect(0, 0, 80, 15))
+	d := widget.NewCanvas(g, "gridD", image.Rect(0, 0, 10, 10))
+	e := widget.NewCanvas(g, "gridE", image.Rect(150, 70, 160, 80))
+	g.Place(a, 0, 0, 1, 1)
+	g.Place(b, 0, 1, 1, 1)
+	g.Place(c, 1, 0, 1, 2)
+	g.Place(d, 2, 1, 1, 1)
+	g.SetAlign(d, widget.AlignRight, widget.AlignBottom)
+	tt := sparttest.New(t, m)
+
+	// a spanning child widens the columns it spans, and the children
+	// fill its cell by default
+	expectGeometry(t, a, image.Rect(5, 5, 35, 25))
+	expectGeometry(t, b, image.Rect(45, 5, 85, 25))
+	expectGeometry(t, c, image.Rect(5, 29, 85, 44))
+	expectGeometry(t, d, image.Rect(75, 48, 85, 58))
+
+	// the children not placed are not laid out
+	expectGeometry(t, e, image.Rect(150, 70, 160, 80))
+
+	// the space left is shared in proportion to the weights
+	g.SetColWeight(0, 1)
+	g.SetColWeight(1, 3)
+	g.SetRowWeight(2, 1)
+	tt.Idle()
+	expectGeometry(t, a, image.Rect(5, 5, 62, 25))
+	expectGeometry(t, b, image.Rect(72, 5, 195, 25))
+	expectGeometry(t, c, image.Rect(5, 29, 195, 44))
+	expectGeometry(t, d, image.Rect(185, 85, 195, 95))
+
+	g.SetAlign(d, widget.AlignCenter, widget.AlignMiddle)
+	tt.Idle()
+	expectGeometry(t, d, image.Rect(128, 66, 138, 76))
+
+	// the missing space is also shared by weight, and a child larger
+	// than its cell fills it
+	tt.Configure("grid", image.Rect(0, 0, 60, 60))
+	expectGeometry(t, a, image.Rect(5, 5, 28, 25))
+	expectGeometry(t, c, image.Rect(5, 29, 55, 44))
+	expectGeometry(t, d, image.Rect(41, 48, 51, 55))
+	m.Close()
+}
@@ -0,0 +1,338 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/js-arias/sparta"
+)
+
+// Alignment is the alignment of a text inside a widget.
+type Alignment int
+
+// Horizontal alignments.
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// Vertical alignments.
+const (
+	AlignTop Alignment = iota
+	AlignMiddle
+	AlignBottom
+)
+
+// Label is a widget that shows a static text. The text can have multiple
+// lines, and optionally, can be wrapped to the width of the label.
+type Label struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	border     bool
+	data       interface{}
+	widgetState
+
+	caption string
+	align   Alignment
+	valign  Alignment
+	wrap    bool
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// Label particular properties.
+const (
+	// sets the horizontal alignment of the text (Alignment): AlignLeft,
+	// AlignCenter or AlignRight.
+	LabelAlign sparta.Property = "align"
+
+	// sets the vertical alignment of the text (Alignment): AlignTop,
+	// AlignMiddle or AlignBottom.
+	LabelVAlign = "valign"
+
+	// sets the word wrapping of the text (bool).
+	LabelWrap = "wrap"
+)
+
+// NewLabel creates a new label.
+func NewLabel(parent sparta.Widget, name, caption string, rect image.Rectangle) *Label {
+	l := &Label{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		caption:  caption,
+	}
+	sparta.NewWindow(l)
+	return l
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// label.
+func (l *Label) SetWindow(win sparta.Window) {
+	l.win = win
+}
+
+// Window returns the backend window.
+func (l *Label) Window() sparta.Window {
+	return l.win
+}
+
+// RemoveWindow removes the backend window.
+func (l *Label) RemoveWindow() {
+	l.win = nil
+}
+
+// Property returns the indicated property of the label.
+func (l *Label) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Caption:
+		return l.caption
+	case sparta.Data:
+		return l.data
+	case sparta.Geometry:
+		return l.geometry
+	case sparta.Parent:
+		return l.parent
+	case sparta.Name:
+		return l.name
+	case sparta.Foreground:
+		return l.fore
+	case sparta.Background:
+		return l.back
+	case sparta.Border:
+		return l.border
+	case LabelAlign:
+		return l.align
+	case LabelVAlign:
+		return l.valign
+	case LabelWrap:
+		return l.wrap
+	case sparta.PrefSize:
+		return l.prefSize()
+	}
+	return l.property(p)
+}
+
+// SetProperty sets a property of the label.
+func (l *Label) SetProperty(p sparta.Property, v interface{}) {
+	if l.setProperty(l.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Caption:
+		val := v.(string)
+		if l.caption != val {
+			l.caption = val
+			l.Update()
+		}
+	case sparta.Data:
+		l.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !l.geometry.Eq(val) {
+			l.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			l.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if l.name != val {
+			l.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if l.fore != val {
+			l.fore = val
+			l.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if l.back != val {
+			l.back = val
+			l.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Border:
+		val := v.(bool)
+		if l.border != val {
+			l.border = val
+			l.Update()
+		}
+	case LabelAlign:
+		val := v.(Alignment)
+		if l.align != val {
+			l.align = val
+			l.Update()
+		}
+	case LabelVAlign:
+		val := v.(Alignment)
+		if l.valign != val {
+			l.valign = val
+			l.Update()
+		}
+	case LabelWrap:
+		val := v.(bool)
+		if l.wrap != val {
+			l.wrap = val
+			l.Update()
+		}
+	}
+}
+
+// Capture sets an event function of the label.
+func (l *Label) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		l.closeFn = fn
+	case sparta.Configure:
+		l.configFn = fn
+	case sparta.Command:
+		l.commFn = fn
+	case sparta.Expose:
+		l.exposeFn = fn
+	case sparta.KeyEv:
+		l.keyFn = fn
+	case sparta.Mouse:
+		l.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the label.
+func (l *Label) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if l.closeFn != nil {
+			l.closeFn(l, e)
+		}
+	case sparta.ConfigureEvent:
+		l.geometry = e.(sparta.ConfigureEvent).Rect
+		if l.configFn != nil {
+			l.configFn(l, e)
+		}
+	case sparta.CommandEvent:
+		if l.commFn != nil {
+			if l.commFn(l, e) {
+				return
+			}
+		}
+		l.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if l.exposeFn != nil {
+			l.exposeFn(l, e)
+		}
+		l.win.SetColor(sparta.Foreground, foreColor)
+		lines := strings.Split(l.caption, "\n")
+		if l.wrap {
+			lines = wrapText(lines, (l.geometry.Dx()-4)/sparta.WidthUnit)
+		}
+		y := 2
+		switch l.valign {
+		case AlignMiddle:
+			y = (l.geometry.Dy() - (len(lines) * sparta.HeightUnit)) / 2
+		case AlignBottom:
+			y = l.geometry.Dy() - (len(lines) * sparta.HeightUnit) - 2
+		}
+		for i, ln := range lines {
+			x := 2
+			switch l.align {
+			case AlignCenter:
+				x = (l.geometry.Dx() - (utf8.RuneCountInString(ln) * sparta.WidthUnit)) / 2
+			case AlignRight:
+				x = l.geometry.Dx() - (utf8.RuneCountInString(ln) * sparta.WidthUnit) - 2
+			}
+			l.win.Text(image.Pt(x, y+(i*sparta.HeightUnit)), ln)
+		}
+		if l.border {
+			rect := image.Rect(0, 0, l.geometry.Dx()-1, l.geometry.Dy()-1)
+			l.win.Rectangle(rect, false)
+		}
+	case sparta.KeyEvent:
+		if l.keyFn != nil {
+			if l.keyFn(l, e) {
+				return
+			}
+		}
+		l.parent.OnEvent(e)
+	case sparta.MouseEvent:
+		if l.mouseFn != nil {
+			l.mouseFn(l, e)
+		}
+	}
+}
+
+// WrapText wraps a set of lines, so each line has at most the indicated
+// number of characters. Lines are broken at spaces, unless a word is
+// longer than the line.
+func wrapText(lines []string, cols int) []string {
+	if cols < 1 {
+		cols = 1
+	}
+	var wrap []string
+	for _, ln := range lines {
+		words := strings.Fields(ln)
+		if len(words) == 0 {
+			wrap = append(wrap, "")
+			continue
+		}
+		var cur []rune
+		for _, w := range words {
+			r := []rune(w)
+			if (len(cur) > 0) && (len(cur)+1+len(r) > cols) {
+				wrap = append(wrap, string(cur))
+				cur = nil
+			}
+			if len(cur) > 0 {
+				cur = append(cur, ' ')
+			}
+			cur = append(cur, r...)
+			for len(cur) > cols {
+				wrap = append(wrap, string(cur[:cols]))
+				cur = cur[cols:]
+			}
+		}
+		if len(cur) > 0 {
+			wrap = append(wrap, string(cur))
+		}
+	}
+	return wrap
+}
+
+// PrefSize returns the size of the text of the label.
+func (l *Label) prefSize() image.Point {
+	lines := strings.Split(l.caption, "\n")
+	w := 0
+	for _, ln := range lines {
+		if n := len([]rune(ln)); n > w {
+			w = n
+		}
+	}
+	return image.Pt(w*sparta.WidthUnit+4, len(lines)*sparta.HeightUnit+4)
+}
+
+// Update updates the label.
+func (l *Label) Update() {
+	l.win.Update()
+}
+
+// Focus set the focus on the label.
+func (l *Label) Focus() {
+	l.win.Focus()
+}
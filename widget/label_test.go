@@ -0,0 +1,99 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+// Ink returns the bounds of the drawn (non white) pixels of a widget.
+func ink(tt *sparttest.Tester, name string) image.Rectangle {
+	img := tt.Image(name)
+	var r image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			if (c.R == 255) && (c.G == 255) && (c.B == 255) {
+				continue
+			}
+			r = r.Union(image.Rect(x, y, x+1, y+1))
+		}
+	}
+	return r
+}
+
+func TestLabelAlign(t *testing.T) {
+	m := widget.NewMainWindow("alignLabelMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 100))
+	l := widget.NewLabel(m, "alignLabel", "MMMM", image.Rect(0, 0, 200, 100))
+	tt := sparttest.New(t, m)
+	tests := []struct {
+		align, valign widget.Alignment
+		check         func(left, right, top, bottom int) bool
+	}{
+		{widget.AlignLeft, widget.AlignTop, func(l, r, t, b int) bool { return l < 4 && t < 6 }},
+		{widget.AlignRight, widget.AlignBottom, func(l, r, t, b int) bool { return r < 4 && b < 10 }},
+		{widget.AlignCenter, widget.AlignMiddle, func(l, r, t, b int) bool {
+			return abs(l-r) <= sparta.WidthUnit && abs(t-b) <= sparta.HeightUnit/2
+		}},
+	}
+	for _, test := range tests {
+		l.SetProperty(widget.LabelAlign, test.align)
+		l.SetProperty(widget.LabelVAlign, test.valign)
+		tt.Idle()
+		r := ink(tt, "alignLabel")
+		if r.Empty() {
+			t.Fatalf("align %d, %d: empty label", test.align, test.valign)
+		}
+		left, right, top, bottom := r.Min.X, 200-r.Max.X, r.Min.Y, 100-r.Max.Y
+		if !test.check(left, right, top, bottom) {
+			t.Errorf("align %d, %d: text at %v", test.align, test.valign, r)
+		}
+	}
+	m.Close()
+}
+
+func TestLabelWrap(t *testing.T) {
+	m := widget.NewMainWindow("wrapLabelMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 100))
+	l := widget.NewLabel(m, "wrapLabel", "aaaa bbbb cccc", image.Rect(0, 0, 40, 100))
+	tt := sparttest.New(t, m)
+	if r := ink(tt, "wrapLabel"); r.Dy() > sparta.HeightUnit {
+		t.Errorf("unwrapped text with height %d", r.Dy())
+	}
+	if r := ink(tt, "wrapLabel"); r.Max.X < 40-4 {
+		t.Errorf("unwrapped text ends at %d", r.Max.X)
+	}
+
+	// each word is in its own line
+	l.SetProperty(widget.LabelWrap, true)
+	tt.Idle()
+	r := ink(tt, "wrapLabel")
+	if r.Dy() <= 2*sparta.HeightUnit {
+		t.Errorf("wrapped text with height %d, want 3 lines", r.Dy())
+	}
+	if r.Max.X > 2+4*sparta.WidthUnit {
+		t.Errorf("wrapped text ends at %d", r.Max.X)
+	}
+
+	// the preferred size is the size of the unwrapped text
+	if s := l.Property(sparta.PrefSize).(image.Point); s != image.Pt(14*sparta.WidthUnit+4, sparta.HeightUnit+4) {
+		t.Errorf("preferred size %v", s)
+	}
+	m.Close()
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -7,6 +7,10 @@ package widget
 import (
 	"image"
 	"image/color"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/js-arias/sparta"
 )
@@ -23,15 +27,84 @@ type ListData interface {
 	IsSel(int) bool
 }
 
+// ListColumns is a list with columns. If the list of a list control
+// implements it, the elements are shown as rows of cells, instead of
+// the Item strings.
+type ListColumns interface {
+	ListData
+
+	// Cols returns the number of columns of the list.
+	Cols() int
+
+	// Cell returns the text of a column of the i-th element.
+	Cell(i, col int) string
+}
+
+// ListColors is a list with a foreground color for each element.
+type ListColors interface {
+	ListData
+
+	// Color returns the color of the i-th element.
+	Color(int) color.RGBA
+}
+
+// ListImages is a list with a small image for each element. The images
+// are clipped to the height of a row.
+type ListImages interface {
+	ListData
+
+	// Image returns the image of the i-th element, or nil if the
+	// element has no image.
+	Image(int) image.Image
+}
+
+// SelectMode is a selection model of a list.
+type SelectMode int
+
+// List selection models.
+const (
+	// the selection is managed by client code
+	SelectClient SelectMode = iota
+
+	// only one element can be selected
+	SelectSingle
+
+	// each element is toggled, independently of the others
+	SelectMultiple
+
+	// a click selects a single element, with Ctrl it is toggled, and
+	// with Shift a range of elements is selected
+	SelectExtended
+)
+
 // List is a widget that shows a list of strings, and one or more elements
-// can be selected with the mouse.
+// can be selected with the mouse. If the list data implements ListColumns,
+// ListColors or ListImages, the elements are shown in columns, with its
+// own colors or with a small image. When the elements are wider than the
+// list, a horizontal scroll is shown.
 //
 // When a element of the list is selected, it send a comand event to its target
 // widget indicating the index of the selected element. It will be a positive
 // number if the selection is made with the left button or negative (starting
 // at -1), if it was with the right button.
 //
-// It is up to client code to manage multiple or single selection.
+// By default it is up to client code to manage multiple or single
+// selection. If the property ListSelMode is set, the list manages the
+// selection by itself (the IsSel method of the list data is ignored): the
+// cursor is moved with the arrow keys, the space key selects the element
+// at the cursor, Shift with the arrow keys selects a range of elements,
+// and Ctrl+A selects all the elements. Then, when the selection changes,
+// the command event sent to the target widget has the index of the
+// element at the cursor as its value (as a click in the default mode),
+// and the selected elements are retrieved with the property
+// ListSelection. A right click is still sent as a negative number.
+//
+// When a printable key is typed, the list moves to the next element that
+// starts with the typed text, the typed text is reset after a second
+// without typing. If the property ListFilter is set, the typed text is
+// used as a filter, and only the elements that start with the typed text
+// are shown (the backspace key removes the last typed character, and the
+// escape key clears the filter).
 type List struct {
 	name       string
 	win        sparta.Window
@@ -39,10 +112,22 @@ type List struct {
 	geometry   image.Rectangle
 	fore, back color.RGBA
 	data       interface{}
+	widgetState
 
 	list   ListData
 	target sparta.Widget
 	scroll *Scroll
+	hs     *Scroll
+	widths []int
+	width  int // width of the widest element
+	mode   SelectMode
+	sel    map[int]bool
+	cur    int // cursor
+	pivot  int // start of a range selection
+	filter bool
+	typed  []rune
+	last   time.Time // time of the last typed key
+	shown  []int     // index of the shown elements, if the list is filtered
 
 	closeFn  func(sparta.Widget, interface{}) bool
 	commFn   func(sparta.Widget, interface{}) bool
@@ -56,8 +141,32 @@ type List struct {
 const (
 	// sets the string list
 	ListList sparta.Property = "list"
+
+	// sets the width, in pixels, of the columns of the list ([]int)
+	ListColWidths = "colwidths"
+
+	// sets the selection model of the list (SelectMode)
+	ListSelMode = "selmode"
+
+	// sets the indexes of the selected elements ([]int), when the list
+	// manages the selection. Setting it does not send a command event.
+	ListSelection = "selection"
+
+	// sets the filter mode of the list (bool)
+	ListFilter = "filter"
+
+	// sets the text used to filter the list (string)
+	ListFilterText = "filtertext"
 )
 
+// listTypeTimeout is the time after which the typed text of a list is
+// reset.
+const listTypeTimeout = time.Second
+
+// listNow returns the current time, used to reset the typed text of a
+// list.
+var listNow = time.Now
+
 // NewList creates a new list.
 func NewList(parent sparta.Widget, name string, rect image.Rectangle) *List {
 	l := &List{
@@ -70,6 +179,8 @@ func NewList(parent sparta.Widget, name string, rect image.Rectangle) *List {
 	}
 	sparta.NewWindow(l)
 	l.scroll = NewScroll(l, "list"+name+"Scroll", 0, 0, Vertical, image.Rect(rect.Dx()-10, 0, rect.Dx(), rect.Dy()))
+	l.hs = NewScroll(l, "list"+name+"HScroll", 0, 0, Horizontal, image.Rect(0, rect.Dy()-10, rect.Dx()-10, rect.Dy()))
+	l.hs.SetProperty(sparta.Visible, false)
 	return l
 }
 
@@ -92,7 +203,7 @@ func (l *List) RemoveWindow() {
 func (l *List) Property(p sparta.Property) interface{} {
 	switch p {
 	case sparta.Childs:
-		return []sparta.Widget{l.scroll}
+		return []sparta.Widget{l.scroll, l.hs}
 	case sparta.Data:
 		return l.data
 	case sparta.Geometry:
@@ -109,16 +220,35 @@ func (l *List) Property(p sparta.Property) interface{} {
 		return l.target
 	case ListList:
 		return l.list
+	case ListColWidths:
+		return l.widths
+	case ListSelMode:
+		return l.mode
+	case ListSelection:
+		return l.selection()
+	case ListFilter:
+		return l.filter
+	case ListFilterText:
+		if !l.filter {
+			return ""
+		}
+		return string(l.typed)
+	case sparta.PrefSize:
+		return image.Pt(21*sparta.WidthUnit+12, 8*sparta.HeightUnit+4)
 	}
-	return nil
+	return l.property(p)
 }
 
 // SetProperty sets a property of the list.
 func (l *List) SetProperty(p sparta.Property, v interface{}) {
+	if l.setProperty(l.win, p, v) {
+		return
+	}
 	switch p {
 	case sparta.Childs:
 		if v == nil {
 			l.scroll = nil
+			l.hs = nil
 		}
 	case sparta.Data:
 		l.data = v
@@ -160,15 +290,55 @@ func (l *List) SetProperty(p sparta.Property, v interface{}) {
 	case ListList:
 		if v == nil {
 			l.list = nil
-			l.scroll.SetProperty(ScrollSize, 0)
 		} else {
-			val := v.(ListData)
-			l.list = val
-			l.scroll.SetProperty(ScrollSize, 0)
-			l.scroll.SetProperty(ScrollSize, val.Len())
+			l.list = v.(ListData)
+		}
+		l.hs.SetProperty(ScrollPos, 0)
+		l.setWidth()
+		l.sel = nil
+		l.setFilter(string(l.typed))
+	case ListColWidths:
+		if v == nil {
+			l.widths = nil
+		} else {
+			l.widths = append([]int(nil), v.([]int)...)
 		}
-		l.scroll.SetProperty(ScrollPage, l.geometry.Dy()/sparta.HeightUnit)
+		l.setWidth()
 		l.Update()
+	case ListSelMode:
+		val := v.(SelectMode)
+		if l.mode != val {
+			l.mode = val
+			l.sel = nil
+			l.Update()
+		}
+	case ListSelection:
+		l.sel = nil
+		if v != nil {
+			for _, i := range v.([]int) {
+				if (l.list == nil) || (i < 0) || (i >= l.list.Len()) {
+					continue
+				}
+				if l.sel == nil {
+					l.sel = make(map[int]bool)
+				}
+				l.sel[i] = true
+				if l.mode == SelectSingle {
+					break
+				}
+			}
+		}
+		l.Update()
+	case ListFilter:
+		val := v.(bool)
+		if l.filter != val {
+			l.filter = val
+			l.setFilter("")
+		}
+	case ListFilterText:
+		if l.filter {
+			l.setFilter(v.(string))
+		}
 	}
 }
 
@@ -186,6 +356,7 @@ func (l *List) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) b
 	case sparta.KeyEv:
 		l.keyFn = fn
 		l.scroll.Capture(e, fn)
+		l.hs.Capture(e, fn)
 	case sparta.Mouse:
 		l.mouseFn = fn
 	}
@@ -199,6 +370,7 @@ func (l *List) OnEvent(e interface{}) {
 			l.closeFn(l, e)
 		}
 		l.scroll.OnEvent(e)
+		l.hs.OnEvent(e)
 	case sparta.ConfigureEvent:
 		rect := e.(sparta.ConfigureEvent).Rect
 		l.geometry = rect
@@ -206,7 +378,9 @@ func (l *List) OnEvent(e interface{}) {
 			l.configFn(l, e)
 		}
 		l.scroll.SetProperty(sparta.Geometry, image.Rect(rect.Dx()-10, 0, rect.Dx(), rect.Dy()))
-		l.scroll.SetProperty(ScrollPage, l.geometry.Dy()/sparta.HeightUnit)
+		l.hs.SetProperty(sparta.Geometry, image.Rect(0, rect.Dy()-10, rect.Dx()-10, rect.Dy()))
+		l.scroll.SetProperty(ScrollPage, l.page())
+		l.setHScroll()
 	case sparta.CommandEvent:
 		if l.commFn != nil {
 			if l.commFn(l, e) {
@@ -214,7 +388,7 @@ func (l *List) OnEvent(e interface{}) {
 			}
 		}
 		ev := e.(sparta.CommandEvent)
-		if ev.Source == l.scroll {
+		if (ev.Source == l.scroll) || (ev.Source == l.hs) {
 			l.Update()
 			return
 		}
@@ -223,36 +397,22 @@ func (l *List) OnEvent(e interface{}) {
 		if l.exposeFn != nil {
 			l.exposeFn(l, e)
 		}
-		l.win.SetColor(sparta.Foreground, foreColor)
-		if (l.list != nil) && (l.list.Len() > 0) {
-			pos := l.scroll.Property(ScrollPos).(int)
-			if pos < 0 {
-				pos = 0
-			}
-			page := l.scroll.Property(ScrollPage).(int)
-			for i := 0; i <= page; i++ {
-				j := i + pos
-				if j >= l.list.Len() {
-					break
-				}
-				if l.list.IsSel(j) {
-					y := (i * sparta.HeightUnit) + 2
-					l.win.Text(image.Pt(2, y), ">")
-				}
-				l.win.Text(image.Pt(2+sparta.WidthUnit, (i*sparta.HeightUnit)+2), l.list.Item(j))
-			}
-		}
-		rect := image.Rect(0, 0, l.geometry.Dx()-1, l.geometry.Dy()-1)
-		l.win.Rectangle(rect, false)
+		l.draw()
 	case sparta.KeyEvent:
-		if s.keyFn != nil {
-			if s.keyFn(s, e) {
+		if l.keyFn != nil {
+			if l.keyFn(l, e) {
 				return
 			}
 		}
 		pos := l.scroll.Property(ScrollPos).(int)
 		page := l.scroll.Property(ScrollPage).(int)
 		ev := e.(sparta.KeyEvent)
+		if l.typeKey(ev) {
+			return
+		}
+		if (l.mode != SelectClient) && l.selKey(ev) {
+			return
+		}
 		switch ev.Key {
 		case sparta.KeyDown:
 			l.scroll.SetProperty(ScrollPos, pos+1)
@@ -265,7 +425,17 @@ func (l *List) OnEvent(e interface{}) {
 		case sparta.KeyHome:
 			l.scroll.SetProperty(ScrollPos, 0)
 		case sparta.KeyEnd:
-			s.SetProperty(ScrollPos, l.list.Len())
+			l.scroll.SetProperty(ScrollPos, l.rows())
+		case sparta.KeyLeft, sparta.KeyRight:
+			if !l.hs.Property(sparta.Visible).(bool) {
+				l.parent.OnEvent(e)
+				break
+			}
+			step := sparta.WidthUnit
+			if ev.Key == sparta.KeyLeft {
+				step = -step
+			}
+			l.hs.SetProperty(ScrollPos, l.hs.Property(ScrollPos).(int)+step)
 		default:
 			l.parent.OnEvent(e)
 		}
@@ -284,10 +454,20 @@ func (l *List) OnEvent(e interface{}) {
 			l.scroll.SetProperty(ScrollPos, pos-1)
 		case sparta.MouseLeft:
 			p := ((ev.Loc.Y - 2) / sparta.HeightUnit) + pos
-			sparta.SendEvent(l.target, sparta.CommandEvent{Source: l, Value: p})
+			if l.mode != SelectClient {
+				l.clickSel(p, ev.State)
+				break
+			}
+			if (l.shown != nil) && ((p < 0) || (p >= len(l.shown))) {
+				break
+			}
+			sparta.SendEvent(l.target, sparta.CommandEvent{Source: l, Value: l.index(p)})
 		case sparta.MouseRight:
 			p := ((ev.Loc.Y - 2) / sparta.HeightUnit) + pos
-			sparta.SendEvent(l.target, sparta.CommandEvent{Source: l, Value: -(p + 1)})
+			if (l.shown != nil) && ((p < 0) || (p >= len(l.shown))) {
+				break
+			}
+			sparta.SendEvent(l.target, sparta.CommandEvent{Source: l, Value: -(l.index(p) + 1)})
 		}
 	}
 }
@@ -301,3 +481,472 @@ func (l *List) Update() {
 func (l *List) Focus() {
 	l.win.Focus()
 }
+
+// SelectAll selects all the shown elements of the list, if the list
+// manages a multiple, or extended, selection.
+func (l *List) SelectAll() {
+	if (l.list == nil) || ((l.mode != SelectMultiple) && (l.mode != SelectExtended)) {
+		return
+	}
+	sel := make(map[int]bool)
+	for i, n := 0, l.rows(); i < n; i++ {
+		sel[l.index(i)] = true
+	}
+	l.setSel(sel)
+}
+
+// Rows returns the number of shown elements.
+func (l *List) rows() int {
+	if l.list == nil {
+		return 0
+	}
+	if l.shown != nil {
+		return len(l.shown)
+	}
+	return l.list.Len()
+}
+
+// Index returns the index, in the list data, of a shown element.
+func (l *List) index(row int) int {
+	if l.shown != nil {
+		return l.shown[row]
+	}
+	return row
+}
+
+// IsSel returns true if the i-th element of the list is selected.
+func (l *List) isSel(i int) bool {
+	if l.mode == SelectClient {
+		return l.list.IsSel(i)
+	}
+	return l.sel[i]
+}
+
+// Selection returns the indexes of the selected elements.
+func (l *List) selection() []int {
+	var sel []int
+	for i := range l.sel {
+		sel = append(sel, i)
+	}
+	sort.Ints(sel)
+	return sel
+}
+
+// SetSel sets the selected elements, and if the selection changes, sends
+// the index of the element at the cursor to the target.
+func (l *List) setSel(sel map[int]bool) {
+	eq := len(sel) == len(l.sel)
+	for i := range sel {
+		if !eq {
+			break
+		}
+		eq = l.sel[i]
+	}
+	if eq {
+		return
+	}
+	l.sel = sel
+	l.Update()
+	cur := l.cur
+	if cur < l.rows() {
+		cur = l.index(cur)
+	}
+	sparta.SendEvent(l.target, sparta.CommandEvent{Source: l, Value: cur})
+}
+
+// SelectRange selects the shown elements between two rows. If add is
+// false, any other element is unselected.
+func (l *List) selectRange(from, to int, add bool) {
+	if from > to {
+		from, to = to, from
+	}
+	sel := make(map[int]bool)
+	if add {
+		for i := range l.sel {
+			sel[i] = true
+		}
+	}
+	for i := from; i <= to; i++ {
+		sel[l.index(i)] = true
+	}
+	l.setSel(sel)
+}
+
+// Toggle toggles the selection of a shown element.
+func (l *List) toggle(row int) {
+	i := l.index(row)
+	sel := make(map[int]bool)
+	for j := range l.sel {
+		sel[j] = true
+	}
+	if sel[i] {
+		delete(sel, i)
+	} else {
+		sel[i] = true
+	}
+	l.setSel(sel)
+}
+
+// ClickSel changes the selection when an element is clicked.
+func (l *List) clickSel(i int, state sparta.StateKey) {
+	if (i < 0) || (i >= l.rows()) {
+		return
+	}
+	l.cur = i
+	l.Update()
+	switch {
+	case l.mode == SelectSingle:
+		l.selectRange(i, i, false)
+	case l.mode == SelectMultiple:
+		l.toggle(i)
+	case (state & sparta.StateShift) != 0:
+		l.selectRange(l.pivot, i, (state&sparta.StateCtrl) != 0)
+		return
+	case (state & sparta.StateCtrl) != 0:
+		l.toggle(i)
+	default:
+		l.selectRange(i, i, false)
+	}
+	l.pivot = i
+}
+
+// SelKey process the keys that move the cursor, or change the
+// selection. It returns true if the key is processed.
+func (l *List) selKey(ev sparta.KeyEvent) bool {
+	if l.rows() == 0 {
+		return false
+	}
+	shift := (ev.State & sparta.StateShift) != 0
+	ctrl := (ev.State & sparta.StateCtrl) != 0
+	cur := l.cur
+	switch ev.Key {
+	case sparta.KeyDown:
+		cur++
+	case sparta.KeyUp:
+		cur--
+	case sparta.KeyPageDown:
+		cur += l.page()
+	case sparta.KeyPageUp:
+		cur -= l.page()
+	case sparta.KeyHome:
+		cur = 0
+	case sparta.KeyEnd:
+		cur = l.rows() - 1
+	case ' ':
+		if l.mode == SelectSingle {
+			l.selectRange(l.cur, l.cur, false)
+		} else {
+			l.toggle(l.cur)
+		}
+		l.pivot = l.cur
+		return true
+	case 'a', 'A':
+		if !ctrl {
+			return false
+		}
+		l.SelectAll()
+		return true
+	default:
+		return false
+	}
+	l.moveCur(cur, shift, ctrl)
+	return true
+}
+
+// MoveCur moves the cursor, and changes the selection as the arrow keys
+// do.
+func (l *List) moveCur(cur int, shift, ctrl bool) {
+	if cur >= l.rows() {
+		cur = l.rows() - 1
+	}
+	if cur < 0 {
+		cur = 0
+	}
+	l.cur = cur
+	l.showCur()
+	l.Update()
+	switch {
+	case shift && (l.mode != SelectSingle):
+		l.selectRange(l.pivot, cur, l.mode == SelectMultiple)
+	case ctrl, l.mode == SelectMultiple:
+		// only the cursor is moved
+	default:
+		l.selectRange(cur, cur, false)
+		l.pivot = cur
+	}
+}
+
+// TypeKey process the keys typed to search, or filter, the elements of
+// the list. It returns true if the key is processed.
+func (l *List) typeKey(ev sparta.KeyEvent) bool {
+	if l.list == nil {
+		return false
+	}
+	if l.filter && (len(l.typed) > 0) {
+		switch ev.Key {
+		case sparta.KeyBackSpace:
+			l.setFilter(string(l.typed[:len(l.typed)-1]))
+			return true
+		case sparta.KeyEscape:
+			l.setFilter("")
+			return true
+		}
+	}
+	if ((ev.Key & sparta.KeyNoChar) != 0) || ((ev.State & (sparta.StateCtrl | sparta.StateAlt)) != 0) {
+		return false
+	}
+	r := rune(ev.Key)
+	if !unicode.IsPrint(r) {
+		return false
+	}
+	now := listNow()
+	if !l.filter && (now.Sub(l.last) > listTypeTimeout) {
+		l.typed = nil
+	}
+	if (r == ' ') && (len(l.typed) == 0) {
+		return false
+	}
+	l.last = now
+	if l.filter {
+		l.setFilter(string(append(l.typed, r)))
+		return true
+	}
+	l.typed = append(l.typed, r)
+	l.search()
+	return true
+}
+
+// Search moves to the next element that starts with the typed text.
+func (l *List) search() {
+	txt := strings.ToLower(string(l.typed))
+	start := l.cur
+	if l.mode == SelectClient {
+		start = l.scroll.Property(ScrollPos).(int)
+		if start < 0 {
+			start = 0
+		}
+	}
+	if len(l.typed) == 1 {
+		// a new search starts after the current element
+		start++
+	}
+	n := l.rows()
+	for k := 0; k < n; k++ {
+		row := (start + k) % n
+		if !strings.HasPrefix(strings.ToLower(l.list.Item(l.index(row))), txt) {
+			continue
+		}
+		if l.mode == SelectClient {
+			l.scroll.SetProperty(ScrollPos, row)
+			return
+		}
+		l.moveCur(row, false, false)
+		return
+	}
+}
+
+// SetFilter sets the text used to filter the list, and updates the
+// shown elements.
+func (l *List) setFilter(txt string) {
+	l.typed = []rune(txt)
+	l.shown = nil
+	if l.filter && (len(l.typed) > 0) && (l.list != nil) {
+		txt = strings.ToLower(txt)
+		l.shown = []int{}
+		for i, n := 0, l.list.Len(); i < n; i++ {
+			if strings.HasPrefix(strings.ToLower(l.list.Item(i)), txt) {
+				l.shown = append(l.shown, i)
+			}
+		}
+	}
+	l.cur, l.pivot = 0, 0
+	l.scroll.SetProperty(ScrollSize, 0)
+	l.scroll.SetProperty(ScrollSize, l.rows())
+	l.scroll.SetProperty(ScrollPage, l.page())
+	l.Update()
+}
+
+// ShowCur scrolls the list to show the cursor.
+func (l *List) showCur() {
+	pos := l.scroll.Property(ScrollPos).(int)
+	page := l.page()
+	if l.cur < pos {
+		l.scroll.SetProperty(ScrollPos, l.cur)
+	} else if (page > 0) && (l.cur >= pos+page) {
+		l.scroll.SetProperty(ScrollPos, l.cur-page+1)
+	}
+}
+
+// Page returns the number of visible elements of the list.
+func (l *List) page() int {
+	h := l.geometry.Dy()
+	if l.hs.Property(sparta.Visible).(bool) {
+		h -= 10
+	}
+	return h / sparta.HeightUnit
+}
+
+// ColWidth returns the width of a column of the list.
+func (l *List) colWidth(col int) int {
+	if col < len(l.widths) {
+		return l.widths[col]
+	}
+	return 16 * sparta.WidthUnit
+}
+
+// SetWidth sets the width of the widest element of the list, and the
+// horizontal scroll. The width is taken from the columns of the list, or
+// from the longest element.
+func (l *List) setWidth() {
+	l.width = 0
+	if l.list != nil {
+		x := 2 + sparta.WidthUnit
+		if _, ok := l.list.(ListImages); ok {
+			x += sparta.HeightUnit
+		}
+		if cols, ok := l.list.(ListColumns); ok {
+			for c, n := 0, cols.Cols(); c < n; c++ {
+				x += l.colWidth(c)
+			}
+		} else {
+			max := 0
+			for i, n := 0, l.list.Len(); i < n; i++ {
+				if k := len([]rune(l.list.Item(i))); k > max {
+					max = k
+				}
+			}
+			x += max * sparta.WidthUnit
+		}
+		l.width = x + 2
+	}
+	l.setHScroll()
+}
+
+// SetHScroll shows the horizontal scroll if the widest element of the
+// list is wider than the list.
+func (l *List) setHScroll() {
+	view := l.geometry.Dx() - 10
+	need := l.width > view
+	l.hs.SetProperty(ScrollSize, l.width)
+	l.hs.SetProperty(ScrollPage, view)
+	if need == l.hs.Property(sparta.Visible).(bool) {
+		return
+	}
+	if !need {
+		l.hs.SetProperty(ScrollPos, 0)
+	}
+	l.hs.SetProperty(sparta.Visible, need)
+	l.scroll.SetProperty(ScrollPage, l.page())
+}
+
+// Draw draws the visible elements of the list.
+func (l *List) draw() {
+	l.win.SetColor(sparta.Foreground, foreColor)
+	if l.rows() > 0 {
+		pos := l.scroll.Property(ScrollPos).(int)
+		if pos < 0 {
+			pos = 0
+		}
+		cols, _ := l.list.(ListColumns)
+		colors, _ := l.list.(ListColors)
+		images, _ := l.list.(ListImages)
+		hpos := l.hs.Property(ScrollPos).(int)
+		if hpos < 0 {
+			hpos = 0
+		}
+		page := l.page()
+		for i := 0; i <= page; i++ {
+			if i+pos >= l.rows() {
+				break
+			}
+			j := l.index(i + pos)
+			y := (i * sparta.HeightUnit) + 2
+			x := 2 - hpos
+			if l.isSel(j) {
+				l.win.Text(image.Pt(x, y), ">")
+			}
+			x += sparta.WidthUnit
+			if images != nil {
+				if img := images.Image(j); img != nil {
+					drawImage(l.win, image.Pt(x, y-1), img)
+				}
+				x += sparta.HeightUnit
+			}
+			if colors != nil {
+				l.win.SetColor(sparta.Foreground, colors.Color(j))
+			}
+			if cols != nil {
+				for c, n := 0, cols.Cols(); c < n; c++ {
+					w := l.colWidth(c)
+					l.win.Text(image.Pt(x, y), cellText(cols.Cell(j, c), w))
+					x += w
+				}
+			} else {
+				it := l.list.Item(j)
+				l.win.Text(image.Pt(x, y), it)
+			}
+			if colors != nil {
+				l.win.SetColor(sparta.Foreground, foreColor)
+			}
+		}
+		if cols != nil {
+			// column lines
+			x := 2 + sparta.WidthUnit - hpos
+			if images != nil {
+				x += sparta.HeightUnit
+			}
+			for c, n := 0, cols.Cols(); c < n-1; c++ {
+				x += l.colWidth(c)
+				l.win.Lines([]image.Point{image.Pt(x-2, 0), image.Pt(x-2, l.geometry.Dy()-1)})
+			}
+		}
+		if (l.mode != SelectClient) && (l.cur >= pos) && (l.cur <= pos+page) {
+			y := ((l.cur - pos) * sparta.HeightUnit) + 2
+			l.win.Rectangle(image.Rect(1, y-2, l.geometry.Dx()-12, y+sparta.HeightUnit-2), false)
+		}
+	}
+	rect := image.Rect(0, 0, l.geometry.Dx()-1, l.geometry.Dy()-1)
+	l.win.Rectangle(rect, false)
+}
+
+// DrawImage draws an image, clipped to the height of a row. Each run of
+// pixels of the same color in a line of the image is drawn at once, and
+// transparent pixels are not drawn.
+func drawImage(win sparta.Window, pt image.Point, img image.Image) {
+	b := img.Bounds()
+	if b.Dx() > sparta.HeightUnit-2 {
+		b.Max.X = b.Min.X + sparta.HeightUnit - 2
+	}
+	if b.Dy() > sparta.HeightUnit-2 {
+		b.Max.Y = b.Min.Y + sparta.HeightUnit - 2
+	}
+	fore := foreColor
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		py := pt.Y + y - b.Min.Y
+		for x := b.Min.X; x < b.Max.X; {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			end := x + 1
+			for ; end < b.Max.X; end++ {
+				if color.RGBAModel.Convert(img.At(end, y)).(color.RGBA) != c {
+					break
+				}
+			}
+			if c.A != 0 {
+				if c != fore {
+					win.SetColor(sparta.Foreground, c)
+					fore = c
+				}
+				px := pt.X + x - b.Min.X
+				if end-x == 1 {
+					win.Pixel(image.Pt(px, py))
+				} else {
+					win.Lines([]image.Point{image.Pt(px, py), image.Pt(px+end-x-1, py)})
+				}
+			}
+			x = end
+		}
+	}
+	if fore != foreColor {
+		win.SetColor(sparta.Foreground, foreColor)
+	}
+}
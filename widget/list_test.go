@@ -0,0 +1,302 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+// Items is a list of strings.
+type items []string
+
+func (l items) Len() int          { return len(l) }
+func (l items) Item(i int) string { return l[i] }
+func (l items) IsSel(i int) bool  { return false }
+
+// NumItems returns a list with n numbered items.
+func numItems(n int) items {
+	l := make(items, n)
+	for i := range l {
+		l[i] = fmt.Sprintf("item %d", i)
+	}
+	return l
+}
+
+// RowPt returns a point inside a row of a list.
+func rowPt(row int) image.Point {
+	return image.Pt(10, 2+row*sparta.HeightUnit+3)
+}
+
+// NewList returns a main window with a list of n items.
+func newList(name string, n int) (*widget.MainWindow, *widget.List) {
+	m := widget.NewMainWindow(name+"Main", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 150, 100))
+	l := widget.NewList(m, name, image.Rect(0, 0, 150, 100))
+	l.SetProperty(widget.ListList, numItems(n))
+	return m, l
+}
+
+func scrollPos(tt *sparttest.Tester, list string) int {
+	return tt.Widget("list" + list + "Scroll").Property(widget.ScrollPos).(int)
+}
+
+func expectSel(t *testing.T, l *widget.List, want ...int) {
+	t.Helper()
+	if got := l.Property(widget.ListSelection).([]int); !reflect.DeepEqual(got, want) {
+		t.Errorf("selection %v, want %v", got, want)
+	}
+}
+
+func TestListKeys(t *testing.T) {
+	m, _ := newList("keyList", 20)
+	tt := sparttest.New(t, m)
+	tests := []struct {
+		key sparta.Key
+		pos int
+	}{
+		{sparta.KeyDown, 1},
+		{sparta.KeyDown, 2},
+		{sparta.KeyUp, 1},
+		{sparta.KeyEnd, 14},
+		{sparta.KeyHome, 0},
+		{sparta.KeyPageDown, 6},
+		{sparta.KeyPageUp, 0},
+	}
+	for _, test := range tests {
+		tt.Type("keyList", test.key)
+		if p := scrollPos(tt, "keyList"); p != test.pos {
+			t.Errorf("key %d: position %d, want %d", test.key, p, test.pos)
+		}
+	}
+	m.Close()
+}
+
+// The key capture function receives the key events of the list, and of its
+// scrolls.
+func TestListCaptureKey(t *testing.T) {
+	m, l := newList("captureList", 20)
+	var keys []sparta.Key
+	l.Capture(sparta.KeyEv, func(w sparta.Widget, e interface{}) bool {
+		if w != sparta.Widget(l) {
+			t.Errorf("capture widget %v, want the list", w)
+		}
+		keys = append(keys, e.(sparta.KeyEvent).Key)
+		return true
+	})
+	tt := sparttest.New(t, m)
+	tt.Key("captureList", sparta.KeyEvent{Key: sparta.KeyDown})
+	tt.Key("captureList", sparta.KeyEvent{Key: sparta.KeyEnd})
+	if p := scrollPos(tt, "captureList"); p != 0 {
+		t.Errorf("position %d, want 0", p)
+	}
+	if want := []sparta.Key{sparta.KeyDown, sparta.KeyEnd}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("captured keys %v, want %v", keys, want)
+	}
+	m.Close()
+}
+
+func TestListClick(t *testing.T) {
+	m, _ := newList("clickList", 20)
+	tt := sparttest.New(t, m)
+	tt.Click("clickList", sparta.MouseLeft, rowPt(2))
+	tt.ExpectCommand("clickListMain", "clickList", 2)
+	tt.Type("clickList", sparta.KeyDown)
+	tt.Click("clickList", sparta.MouseLeft, rowPt(2))
+	tt.ExpectCommand("clickListMain", "clickList", 3)
+	tt.Click("clickList", sparta.MouseRight, rowPt(0))
+	tt.ExpectCommand("clickListMain", "clickList", -2)
+	m.Close()
+}
+
+func TestListSelection(t *testing.T) {
+	m, l := newList("selList", 20)
+	l.SetProperty(widget.ListSelMode, widget.SelectExtended)
+	tt := sparttest.New(t, m)
+
+	// each change of the selection sends the index of the element at
+	// the cursor
+	tests := []struct {
+		ev   interface{}
+		cur  int
+		want []int
+	}{
+		{sparta.MouseEvent{Button: sparta.MouseLeft, Loc: rowPt(1)}, 1, []int{1}},
+		{sparta.MouseEvent{Button: sparta.MouseLeft, State: sparta.StateShift, Loc: rowPt(3)}, 3, []int{1, 2, 3}},
+		{sparta.MouseEvent{Button: sparta.MouseLeft, State: sparta.StateCtrl, Loc: rowPt(2)}, 2, []int{1, 3}},
+		{sparta.KeyEvent{Key: sparta.KeyDown, State: sparta.StateShift}, 3, []int{2, 3}},
+		{sparta.KeyEvent{Key: sparta.KeyDown}, 4, []int{4}},
+	}
+	for _, test := range tests {
+		tt.Commands()
+		switch ev := test.ev.(type) {
+		case sparta.MouseEvent:
+			tt.Mouse("selList", ev)
+			ev.Button = -ev.Button
+			tt.Mouse("selList", ev)
+		case sparta.KeyEvent:
+			tt.Key("selList", ev)
+		}
+		tt.ExpectCommand("selListMain", "selList", test.cur)
+		expectSel(t, l, test.want...)
+	}
+	tt.Key("selList", sparta.KeyEvent{Key: 'a', State: sparta.StateCtrl})
+	if n := len(l.Property(widget.ListSelection).([]int)); n != 20 {
+		t.Errorf("selected %d items, want 20", n)
+	}
+
+	// the right button is still sent as a negative index
+	tt.Commands()
+	tt.Click("selList", sparta.MouseRight, rowPt(5))
+	tt.ExpectCommand("selListMain", "selList", -6)
+
+	l.SetProperty(widget.ListSelMode, widget.SelectMultiple)
+	tt.Commands()
+	tt.Type("selList", sparta.KeyEnd)
+	for _, c := range tt.Commands() {
+		if c.Source == sparta.Widget(l) {
+			t.Errorf("command %+v when the cursor is moved", c)
+		}
+	}
+	tt.Type("selList", ' ')
+	tt.ExpectCommand("selListMain", "selList", 19)
+	expectSel(t, l, 19)
+	tt.Key("selList", sparta.KeyEvent{Key: sparta.KeyUp, State: sparta.StateShift})
+	tt.ExpectCommand("selListMain", "selList", 18)
+	expectSel(t, l, 18, 19)
+
+	l.SetProperty(widget.ListSelMode, widget.SelectSingle)
+	if n := len(l.Property(widget.ListSelection).([]int)); n != 0 {
+		t.Errorf("selected %d items after a mode change, want 0", n)
+	}
+	tt.Commands()
+	l.SetProperty(widget.ListSelection, []int{5, 6})
+	expectSel(t, l, 5)
+	for _, c := range tt.Commands() {
+		if c.Source == sparta.Widget(l) {
+			t.Errorf("command %+v when the selection is set", c)
+		}
+	}
+	m.Close()
+}
+
+// ColItems is a list of items with columns, and an image.
+type colItems struct{ items }
+
+func (l colItems) Cols() int            { return 3 }
+func (l colItems) Cell(i, c int) string { return fmt.Sprintf("%d:%d", i, c) }
+
+func (l colItems) Image(i int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 20; y++ {
+			img.SetRGBA(x, y, red)
+		}
+	}
+	return img
+}
+
+var red = color.RGBA{R: 255, A: 255}
+
+func TestListColumns(t *testing.T) {
+	m := widget.NewMainWindow("colListMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 150, 100))
+	l := widget.NewList(m, "colList", image.Rect(0, 0, 150, 100))
+	l.SetProperty(widget.ListList, colItems{numItems(20)})
+	tt := sparttest.New(t, m)
+	hs := tt.Widget("listcolListHScroll")
+	if !hs.Property(sparta.Visible).(bool) {
+		t.Fatalf("horizontal scroll hidden with wide columns")
+	}
+
+	// the image is drawn after the selection mark
+	tt.ExpectPixel("colList", image.Pt(2+sparta.WidthUnit+1, 3), red)
+
+	tt.Type("colList", sparta.KeyRight, sparta.KeyRight)
+	if p := hs.Property(widget.ScrollPos).(int); p != 2*sparta.WidthUnit {
+		t.Errorf("horizontal position %d, want %d", p, 2*sparta.WidthUnit)
+	}
+	tt.Type("colList", sparta.KeyLeft)
+	if p := hs.Property(widget.ScrollPos).(int); p != sparta.WidthUnit {
+		t.Errorf("horizontal position %d, want %d", p, sparta.WidthUnit)
+	}
+
+	l.SetProperty(widget.ListColWidths, []int{20, 20, 20})
+	tt.Idle()
+	if hs.Property(sparta.Visible).(bool) {
+		t.Errorf("horizontal scroll shown with narrow columns")
+	}
+	m.Close()
+}
+
+func TestListType(t *testing.T) {
+	clock := time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer widget.SetListNow(func() time.Time { return clock })()
+
+	m := widget.NewMainWindow("typeListMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 150, 100))
+	l := widget.NewList(m, "typeList", image.Rect(0, 0, 150, 100))
+	l.SetProperty(widget.ListList, items{"Acer", "Bos taurus", "Canis lupus", "Felis catus", "Homo sapiens", "Homo erectus", "Pan troglodytes", "Zea mays"})
+	l.SetProperty(widget.ListSelMode, widget.SelectSingle)
+	tt := sparttest.New(t, m)
+	tt.TypeString("typeList", "ho")
+	expectSel(t, l, 4)
+	tt.TypeString("typeList", "mo e")
+	expectSel(t, l, 5)
+
+	// the typed text is kept before the timeout
+	clock = clock.Add(500 * time.Millisecond)
+	tt.TypeString("typeList", "x")
+	expectSel(t, l, 5)
+
+	// and reset after it
+	clock = clock.Add(2 * time.Second)
+	tt.TypeString("typeList", "p")
+	expectSel(t, l, 6)
+	m.Close()
+}
+
+func TestListFilter(t *testing.T) {
+	m := widget.NewMainWindow("filterListMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 150, 100))
+	l := widget.NewList(m, "filterList", image.Rect(0, 0, 150, 100))
+	l.SetProperty(widget.ListList, items{"Acer", "Bos taurus", "Canis lupus", "Felis catus", "Homo sapiens", "Homo erectus", "Pan troglodytes", "Zea mays"})
+	l.SetProperty(widget.ListSelMode, widget.SelectSingle)
+	l.SetProperty(widget.ListFilter, true)
+	tt := sparttest.New(t, m)
+
+	// only the elements that start with the typed text are shown
+	tt.TypeString("filterList", "us")
+	if f := l.Property(widget.ListFilterText).(string); f != "us" {
+		t.Errorf("filter %q, want %q", f, "us")
+	}
+	tt.Type("filterList", sparta.KeyHome, ' ')
+	expectSel(t, l)
+	tt.Type("filterList", sparta.KeyEscape)
+	if f := l.Property(widget.ListFilterText).(string); f != "" {
+		t.Errorf("filter %q, want empty", f)
+	}
+
+	tt.TypeString("filterList", "ho")
+	tt.Type("filterList", sparta.KeyHome, sparta.KeyDown)
+	expectSel(t, l, 5)
+	tt.Click("filterList", sparta.MouseLeft, rowPt(0))
+	expectSel(t, l, 4)
+	tt.Type("filterList", sparta.KeyBackSpace, sparta.KeyBackSpace)
+	if f := l.Property(widget.ListFilterText).(string); f != "" {
+		t.Errorf("filter %q, want empty", f)
+	}
+	tt.Click("filterList", sparta.MouseLeft, rowPt(0))
+	expectSel(t, l, 0)
+	m.Close()
+}
@@ -20,8 +20,10 @@ type MainWindow struct {
 	childs     []sparta.Widget
 	fore, back color.RGBA
 	data       interface{}
+	windowState
 
-	title string
+	title  string
+	layout anchorLayout
 
 	commFn   func(sparta.Widget, interface{}) bool
 	closeFn  func(sparta.Widget, interface{}) bool
@@ -40,6 +42,7 @@ func NewMainWindow(name, title string) *MainWindow {
 		fore:     foreColor,
 		title:    title,
 	}
+	w.layout.setSize(nil, w.geometry.Size())
 	sparta.NewWindow(w)
 	w.win.SetProperty(sparta.Caption, w.title)
 	return w
@@ -79,11 +82,14 @@ func (w *MainWindow) Property(p sparta.Property) interface{} {
 	case sparta.Background:
 		return w.back
 	}
-	return nil
+	return w.property(p)
 }
 
 // SetProperty sets a property of the main window.
 func (w *MainWindow) SetProperty(p sparta.Property, v interface{}) {
+	if w.setProperty(w.win, p, v) {
+		return
+	}
 	switch p {
 	case sparta.Caption:
 		val := v.(string)
@@ -102,6 +108,7 @@ func (w *MainWindow) SetProperty(p sparta.Property, v interface{}) {
 	case sparta.Geometry:
 		val := v.(image.Rectangle)
 		if !w.geometry.Eq(val) {
+			w.layout.setSize(w.childs, val.Size())
 			w.win.SetProperty(sparta.Geometry, val)
 		}
 	case sparta.Name:
@@ -166,6 +173,7 @@ func (w *MainWindow) OnEvent(e interface{}) {
 		}
 	case sparta.ConfigureEvent:
 		w.geometry = e.(sparta.ConfigureEvent).Rect
+		w.layout.resize(w.childs, w.geometry.Size())
 		if w.configFn != nil {
 			w.configFn(w, e)
 		}
@@ -175,7 +183,15 @@ func (w *MainWindow) OnEvent(e interface{}) {
 		}
 	case sparta.KeyEvent:
 		if w.keyFn != nil {
-			w.keyFn(w, e)
+			if w.keyFn(w, e) {
+				return
+			}
+		}
+		for _, c := range w.childs {
+			if mb, ok := c.(*MenuBar); ok {
+				mb.shortcut(e.(sparta.KeyEvent))
+				break
+			}
 		}
 	case sparta.MouseEvent:
 		if w.mouseFn != nil {
@@ -0,0 +1,643 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+
+	"github.com/js-arias/sparta"
+)
+
+// disColor is the color used to draw disabled elements.
+var disColor = color.RGBA{R: 128, G: 128, B: 128}
+
+// MenuItem is an item of a menu. If the caption has an '&', the next
+// character is the mnemonic of the item (use "&&" for a literal '&').
+type MenuItem struct {
+	Caption   string
+	Value     int         // value sent to the target
+	Separator bool        // the item is a separator line
+	Check     bool        // the item is a check item
+	Checked   bool        // state of a check item
+	Disabled  bool        // the item can not be selected
+	Sub       []*MenuItem // items of the submenu
+}
+
+// Menu is a popup widget that shows a list of items, that can be selected
+// with the mouse, with the arrow and return keys, or with the mnemonic of
+// the item. When an item is selected, the menu (and all its parent menus)
+// are closed, and a command event with the value of the item is sent to
+// the target widget. The source of the event is the menu bar that opened
+// the menu, or the parent of the menu.
+//
+// If the item has a submenu, the submenu is opened when the pointer is
+// over the item, or with the right arrow key, and closed with the left
+// arrow or escape keys. When a check item is selected, its state is
+// toggled before the event is sent.
+//
+// A menu that is not a submenu grabs the pointer, and it is closed with a
+// click outside of the menu, or with the escape key. A menu can be used
+// as a context menu of any widget with NewPopupMenu.
+type Menu struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	windowState
+
+	items  []*MenuItem
+	target sparta.Widget
+	cur    int
+	sub    *Menu
+	armed  bool          // true if a button release can select an item
+	focus  sparta.Widget // widget with the focus before the menu is opened
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// Menu particular properties.
+const (
+	// sets the items of the menu ([]*MenuItem).
+	MenuItems sparta.Property = "items"
+)
+
+// NewMenu creates a new menu, with the given items, at the given screen
+// position.
+func NewMenu(parent sparta.Widget, name string, items []*MenuItem, pt image.Point) *Menu {
+	m := &Menu{
+		name:   name,
+		parent: parent,
+		back:   backColor,
+		fore:   foreColor,
+		items:  items,
+		target: parent,
+		cur:    -1,
+	}
+	switch p := parent.(type) {
+	case *Menu:
+		m.target = p.target
+	case *MenuBar:
+		m.target = p.target
+	}
+	m.geometry = menuSize(items).Add(pt)
+	if _, ok := parent.(*Menu); !ok {
+		m.focus = sparta.Focused()
+		if m.focus == nil {
+			m.focus = parent
+		}
+	}
+	sparta.NewWindow(m)
+	if _, ok := parent.(*Menu); !ok {
+		sparta.Grab(m)
+	}
+	m.Focus()
+	return m
+}
+
+// NewPopupMenu creates a new menu, as a context menu of a widget, with its
+// top-left corner at the given location of the widget, for example, the
+// location of a mouse event:
+//
+//	w.Capture(sparta.Mouse, func(w sparta.Widget, e interface{}) bool {
+//		ev := e.(sparta.MouseEvent)
+//		if ev.Button != sparta.MouseRight {
+//			return false
+//		}
+//		widget.NewPopupMenu(w, "context", items, ev.Loc)
+//		return true
+//	})
+//
+// The value of the selected item is sent to the widget, unless other
+// target is set with the property Target. An item can be selected with a
+// click, or by dragging the pointer to the item and releasing the button.
+func NewPopupMenu(parent sparta.Widget, name string, items []*MenuItem, loc image.Point) *Menu {
+	return NewMenu(parent, name, items, sparta.RootPos(parent).Add(loc))
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// menu.
+func (m *Menu) SetWindow(win sparta.Window) {
+	m.win = win
+}
+
+// Window returns the backend window.
+func (m *Menu) Window() sparta.Window {
+	return m.win
+}
+
+// RemoveWindow removes the backend window.
+func (m *Menu) RemoveWindow() {
+	m.win = nil
+	if m.sub != nil {
+		m.sub.Close()
+	}
+	m.detach()
+}
+
+// Detach removes the menu from its parent menu, or menu bar. It must be
+// called before the parent is removed, as the backends remove the parent
+// before the window.
+func (m *Menu) detach() {
+	switch p := m.parent.(type) {
+	case *Menu:
+		if p.sub == m {
+			p.sub = nil
+		}
+	case *MenuBar:
+		if p.menu == m {
+			p.menu = nil
+			p.cur = -1
+			if p.win != nil {
+				p.Update()
+			}
+		}
+	}
+}
+
+// Property returns the indicated property of the menu.
+func (m *Menu) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Data:
+		return m.data
+	case sparta.Geometry:
+		return m.geometry
+	case sparta.Parent:
+		return m.parent
+	case sparta.Name:
+		return m.name
+	case sparta.Foreground:
+		return m.fore
+	case sparta.Background:
+		return m.back
+	case sparta.Popup:
+		return true
+	case sparta.Target:
+		return m.target
+	case MenuItems:
+		return m.items
+	}
+	return m.property(p)
+}
+
+// SetProperty sets a property of the menu.
+func (m *Menu) SetProperty(p sparta.Property, v interface{}) {
+	if m.setProperty(m.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Data:
+		m.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !m.geometry.Eq(val) {
+			m.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			m.detach()
+			m.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if m.name != val {
+			m.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if m.fore != val {
+			m.fore = val
+			m.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if m.back != val {
+			m.back = val
+			m.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = m.parent
+		}
+		if m.target == val {
+			break
+		}
+		m.target = val
+	case MenuItems:
+		if m.sub != nil {
+			m.sub.Close()
+		}
+		m.items = v.([]*MenuItem)
+		m.cur = -1
+		m.win.SetProperty(sparta.Geometry, menuSize(m.items).Add(m.geometry.Min))
+		m.Update()
+	}
+}
+
+// Capture sets an event function of the menu.
+func (m *Menu) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		m.closeFn = fn
+	case sparta.Configure:
+		m.configFn = fn
+	case sparta.Command:
+		m.commFn = fn
+	case sparta.Expose:
+		m.exposeFn = fn
+	case sparta.KeyEv:
+		m.keyFn = fn
+	case sparta.Mouse:
+		m.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the menu.
+func (m *Menu) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if m.closeFn != nil {
+			m.closeFn(m, e)
+		}
+	case sparta.ConfigureEvent:
+		m.geometry = e.(sparta.ConfigureEvent).Rect
+		if m.configFn != nil {
+			m.configFn(m, e)
+		}
+	case sparta.CommandEvent:
+		if m.commFn != nil {
+			if m.commFn(m, e) {
+				return
+			}
+		}
+		if m.parent != nil {
+			m.parent.OnEvent(e)
+		}
+	case sparta.ExposeEvent:
+		if m.exposeFn != nil {
+			m.exposeFn(m, e)
+		}
+		m.expose()
+	case sparta.KeyEvent:
+		if m.keyFn != nil {
+			if m.keyFn(m, e) {
+				return
+			}
+		}
+		m.key(e.(sparta.KeyEvent))
+	case sparta.MouseEvent:
+		if m.mouseFn != nil {
+			if m.mouseFn(m, e) {
+				return
+			}
+		}
+		m.mouse(e.(sparta.MouseEvent))
+	}
+}
+
+// Expose draws the menu.
+func (m *Menu) expose() {
+	m.win.SetColor(sparta.Foreground, foreColor)
+	w := m.geometry.Dx()
+	for i, it := range m.items {
+		r := m.itemRect(i)
+		if it.Separator {
+			y := (r.Min.Y + r.Max.Y) / 2
+			m.win.Lines([]image.Point{image.Pt(r.Min.X+2, y), image.Pt(r.Max.X-3, y)})
+			continue
+		}
+		fg, bg := foreColor, backColor
+		if it.Disabled {
+			fg = disColor
+		} else if i == m.cur {
+			m.win.Rectangle(r, true)
+			fg, bg = backColor, foreColor
+		}
+		m.win.SetColor(sparta.Foreground, fg)
+		m.win.SetColor(sparta.Background, bg)
+		y := r.Min.Y + 1
+		if it.Check && it.Checked {
+			x := r.Min.X + 2
+			m.win.Lines([]image.Point{image.Pt(x, y+5), image.Pt(x+2, y+8), image.Pt(x+6, y+2)})
+		}
+		menuText(m.win, image.Pt(r.Min.X+2*sparta.WidthUnit, y), it.Caption)
+		if len(it.Sub) > 0 {
+			x := w - 2*sparta.WidthUnit
+			my := y + sparta.HeightUnit/2
+			m.win.Polygon([]image.Point{image.Pt(x, my-4), image.Pt(x+4, my), image.Pt(x, my+4)}, true)
+		}
+		m.win.SetColor(sparta.Foreground, foreColor)
+		m.win.SetColor(sparta.Background, backColor)
+	}
+	rect := image.Rect(0, 0, w-1, m.geometry.Dy()-1)
+	m.win.Rectangle(rect, false)
+}
+
+// Key process a key press.
+func (m *Menu) key(ev sparta.KeyEvent) {
+	if ev.Key < 0 {
+		return
+	}
+	switch ev.Key {
+	case sparta.KeyDown:
+		m.setCur(m.next(m.cur, 1))
+	case sparta.KeyUp:
+		m.setCur(m.next(m.cur, -1))
+	case sparta.KeyHome:
+		m.setCur(m.next(-1, 1))
+	case sparta.KeyEnd:
+		m.setCur(m.next(len(m.items), -1))
+	case sparta.KeyReturn, sparta.KeyPadEnter, ' ':
+		if m.cur >= 0 {
+			m.activate(m.cur)
+		}
+	case sparta.KeyRight:
+		if (m.cur >= 0) && (len(m.items[m.cur].Sub) > 0) {
+			m.activate(m.cur)
+			return
+		}
+		if b, ok := m.root().parent.(*MenuBar); ok {
+			b.step(1)
+		}
+	case sparta.KeyLeft:
+		if p, ok := m.parent.(*Menu); ok {
+			m.Close()
+			p.Focus()
+			return
+		}
+		if b, ok := m.parent.(*MenuBar); ok {
+			b.step(-1)
+		}
+	case sparta.KeyEscape:
+		if p, ok := m.parent.(*Menu); ok {
+			m.Close()
+			p.Focus()
+			return
+		}
+		m.Close()
+	default:
+		if (ev.Key & sparta.KeyNoChar) != 0 {
+			break
+		}
+		r := unicode.ToLower(rune(ev.Key))
+		for i, it := range m.items {
+			if it.Separator || it.Disabled {
+				continue
+			}
+			if _, mn := mnemonic(it.Caption); mn == r {
+				m.setCur(i)
+				m.activate(i)
+				return
+			}
+		}
+	}
+}
+
+// Mouse process a mouse event.
+func (m *Menu) mouse(ev sparta.MouseEvent) {
+	if !ev.Loc.In(image.Rect(0, 0, m.geometry.Dx(), m.geometry.Dy())) {
+		// events over the menu bar are sent to the menu bar
+		if b, ok := m.parent.(*MenuBar); ok && !b.disabled {
+			pt := ev.Loc.Add(sparta.RootPos(m)).Sub(sparta.RootPos(b))
+			if pt.In(image.Rect(0, 0, b.geometry.Dx(), b.geometry.Dy())) {
+				ev.Loc = pt
+				b.OnEvent(ev)
+				return
+			}
+		}
+		switch ev.Button {
+		case sparta.MouseLeft, sparta.MouseRight, sparta.Mouse2:
+			m.root().Close()
+		}
+		return
+	}
+	i := m.itemAt(ev.Loc)
+	switch ev.Button {
+	case 0:
+		if i < 0 {
+			break
+		}
+		if (ev.State & (sparta.StateButtonL | sparta.StateButtonR)) != 0 {
+			m.armed = true
+		}
+		m.setCur(i)
+		if (m.sub == nil) && !m.items[i].Disabled && (len(m.items[i].Sub) > 0) {
+			m.openSub(false)
+		}
+	case sparta.MouseLeft, sparta.MouseRight:
+		m.armed = true
+		if i >= 0 {
+			m.setCur(i)
+			if len(m.items[i].Sub) > 0 {
+				m.activate(i)
+			}
+		}
+	case -sparta.MouseLeft, -sparta.MouseRight:
+		if !m.armed || (i < 0) || (len(m.items[i].Sub) > 0) {
+			break
+		}
+		m.activate(i)
+	}
+}
+
+// SetCur sets the current item, closing the submenu of the previous
+// current item.
+func (m *Menu) setCur(i int) {
+	if (i < 0) || (m.cur == i) {
+		return
+	}
+	if m.sub != nil {
+		m.sub.Close()
+	}
+	m.cur = i
+	m.Update()
+}
+
+// Next returns the next item that can be selected, starting from a given
+// item, in the given direction. It returns -1 if there is no item that
+// can be selected.
+func (m *Menu) next(from, d int) int {
+	n := len(m.items)
+	i := from
+	for j := 0; j < n; j++ {
+		i = (i + d + n) % n
+		if !m.items[i].Separator && !m.items[i].Disabled {
+			return i
+		}
+	}
+	return -1
+}
+
+// Activate selects an item of the menu. If the item has a submenu, the
+// submenu is opened, otherwise, all the menus are closed and the value of
+// the item is sent to the target.
+func (m *Menu) activate(i int) {
+	it := m.items[i]
+	if it.Separator || it.Disabled {
+		return
+	}
+	if len(it.Sub) > 0 {
+		m.openSub(true)
+		return
+	}
+	if it.Check {
+		it.Checked = !it.Checked
+	}
+	root := m.root()
+	src, target := root.parent, m.target
+	root.Close()
+	if (target == nil) || (src == nil) || (src.Window() == nil) {
+		return
+	}
+	sparta.SendEvent(target, sparta.CommandEvent{Source: src, Value: it.Value})
+}
+
+// OpenSub opens the submenu of the current item. If focus is true the
+// focus is set on the submenu.
+func (m *Menu) openSub(focus bool) {
+	if m.sub == nil {
+		r := m.itemRect(m.cur)
+		pt := sparta.RootPos(m).Add(image.Pt(m.geometry.Dx()-2, r.Min.Y-2))
+		m.sub = NewMenu(m, m.name+"Sub", m.items[m.cur].Sub, pt)
+	}
+	if focus {
+		m.sub.setCur(m.sub.next(-1, 1))
+		m.sub.Focus()
+	} else {
+		m.Focus()
+	}
+}
+
+// Root returns the first menu of a chain of submenus.
+func (m *Menu) root() *Menu {
+	for {
+		p, ok := m.parent.(*Menu)
+		if !ok {
+			return m
+		}
+		m = p
+	}
+}
+
+// ItemRect returns the rectangle of the i-th item.
+func (m *Menu) itemRect(i int) image.Rectangle {
+	y := 2
+	for j := 0; j < i; j++ {
+		y += itemHeight(m.items[j])
+	}
+	return image.Rect(1, y, m.geometry.Dx()-1, y+itemHeight(m.items[i]))
+}
+
+// ItemAt returns the item at the given point, or -1 if there is no
+// selectable item.
+func (m *Menu) itemAt(pt image.Point) int {
+	for i, it := range m.items {
+		if !pt.In(m.itemRect(i)) {
+			continue
+		}
+		if it.Separator || it.Disabled {
+			return -1
+		}
+		return i
+	}
+	return -1
+}
+
+// Update updates the menu.
+func (m *Menu) Update() {
+	m.win.Update()
+}
+
+// Focus set the focus on the menu.
+func (m *Menu) Focus() {
+	m.win.Focus()
+}
+
+// Close closes the menu, and its submenus. If the menu is not a submenu,
+// the focus is given back to the widget that has it before the menu was
+// opened.
+func (m *Menu) Close() {
+	if m.win == nil {
+		return
+	}
+	m.win.Close()
+	if (m.focus != nil) && (m.focus.Window() != nil) {
+		m.focus.Focus()
+	}
+}
+
+// ItemHeight returns the height of a menu item.
+func itemHeight(it *MenuItem) int {
+	if it.Separator {
+		return 6
+	}
+	return sparta.HeightUnit + 2
+}
+
+// MenuSize returns the size of a menu with the given items.
+func menuSize(items []*MenuItem) image.Rectangle {
+	w, h := 0, 4
+	for _, it := range items {
+		h += itemHeight(it)
+		txt, _ := mnemonic(it.Caption)
+		if len(txt) > w {
+			w = len(txt)
+		}
+	}
+	return image.Rect(0, 0, (w+4)*sparta.WidthUnit+4, h)
+}
+
+// Mnemonic returns the text of a caption without the mnemonic mark, and
+// the mnemonic (in lower case) of the caption, or 0 if the caption does
+// not have a mnemonic.
+func mnemonic(caption string) ([]rune, rune) {
+	var txt []rune
+	var mn rune
+	amp := false
+	for _, r := range caption {
+		if (r == '&') && !amp {
+			amp = true
+			continue
+		}
+		if amp && (r != '&') && (mn == 0) {
+			mn = unicode.ToLower(r)
+		}
+		amp = false
+		txt = append(txt, r)
+	}
+	return txt, mn
+}
+
+// MenuText draws the caption of a menu item, with its mnemonic
+// underlined.
+func menuText(win sparta.Window, pt image.Point, caption string) {
+	txt, _ := mnemonic(caption)
+	win.Text(pt, string(txt))
+	amp := false
+	i := 0
+	for _, r := range caption {
+		if (r == '&') && !amp {
+			amp = true
+			continue
+		}
+		if amp && (r != '&') {
+			x := pt.X + (i * sparta.WidthUnit)
+			y := pt.Y + 12
+			win.Lines([]image.Point{image.Pt(x, y), image.Pt(x+sparta.WidthUnit-1, y)})
+			return
+		}
+		amp = false
+		i++
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func TestPopupMenu(t *testing.T) {
+	m := widget.NewMainWindow("menuMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 150))
+	e := widget.NewEntry(m, "menuEntry", image.Rect(10, 10, 150, 30))
+	tt := sparttest.New(t, m)
+	e.Focus()
+	items := []*widget.MenuItem{
+		{Caption: "One", Value: 1},
+		{Caption: "Two", Value: 2},
+	}
+	menu := widget.NewPopupMenu(e, "menuPopup", items, image.Pt(5, 5))
+	tt.Idle()
+	if f := sparta.Focused(); f != sparta.Widget(menu) {
+		t.Fatalf("focus %v, want the menu", f)
+	}
+	tt.Type("menuPopup", sparta.KeyDown, sparta.KeyDown, sparta.KeyReturn)
+	tt.ExpectCommand("menuEntry", "menuEntry", 2)
+	if menu.Window() != nil {
+		t.Errorf("menu not closed")
+	}
+	if f := sparta.Focused(); f != sparta.Widget(e) {
+		t.Errorf("focus %v, want the entry", f)
+	}
+
+	// the focus is restored when the menu is cancelled
+	menu = widget.NewPopupMenu(e, "menuPopup", items, image.Pt(5, 5))
+	tt.Idle()
+	tt.Type("menuPopup", sparta.KeyEscape)
+	if menu.Window() != nil {
+		t.Errorf("menu not closed")
+	}
+	if f := sparta.Focused(); f != sparta.Widget(e) {
+		t.Errorf("focus %v, want the entry", f)
+	}
+	m.Close()
+}
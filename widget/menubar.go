@@ -0,0 +1,368 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+
+	"github.com/js-arias/sparta"
+)
+
+// MenuBar is a widget that shows a row of menu titles, usually at the top
+// of a main window. When a title is clicked, its submenu is opened as a
+// pull-down menu (see Menu). Titles without a submenu send its value to
+// the target widget when clicked.
+//
+// If the menu bar is a child of a main window, the first menu can be
+// opened with the F10 key, and any menu with the alt key and the mnemonic
+// of its title. Once a menu is open, the left and right arrow keys move
+// to the previous and next menus.
+type MenuBar struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	widgetState
+
+	items  []*MenuItem
+	target sparta.Widget
+	cur    int
+	menu   *Menu
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// MenuBar particular properties.
+const (
+	// sets the menu titles of the menu bar ([]*MenuItem).
+	MenuBarItems sparta.Property = "items"
+)
+
+// NewMenuBar creates a new menu bar.
+func NewMenuBar(parent sparta.Widget, name string, rect image.Rectangle) *MenuBar {
+	b := &MenuBar{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		target:   parent,
+		cur:      -1,
+	}
+	sparta.NewWindow(b)
+	return b
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// menu bar.
+func (b *MenuBar) SetWindow(win sparta.Window) {
+	b.win = win
+}
+
+// Window returns the backend window.
+func (b *MenuBar) Window() sparta.Window {
+	return b.win
+}
+
+// RemoveWindow removes the backend window.
+func (b *MenuBar) RemoveWindow() {
+	b.win = nil
+	if b.menu != nil {
+		b.menu.Close()
+	}
+}
+
+// Property returns the indicated property of the menu bar.
+func (b *MenuBar) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Data:
+		return b.data
+	case sparta.Geometry:
+		return b.geometry
+	case sparta.Parent:
+		return b.parent
+	case sparta.Name:
+		return b.name
+	case sparta.Foreground:
+		return b.fore
+	case sparta.Background:
+		return b.back
+	case sparta.Target:
+		return b.target
+	case MenuBarItems:
+		return b.items
+	case sparta.PrefSize:
+		w := 4
+		if n := len(b.items); n > 0 {
+			w = b.titleRect(n-1).Max.X + 2
+		}
+		return image.Pt(w, sparta.HeightUnit+4)
+	}
+	return b.property(p)
+}
+
+// SetProperty sets a property of the menu bar.
+func (b *MenuBar) SetProperty(p sparta.Property, v interface{}) {
+	if b.setProperty(b.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Data:
+		b.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !b.geometry.Eq(val) {
+			b.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			b.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if b.name != val {
+			b.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if b.fore != val {
+			b.fore = val
+			b.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if b.back != val {
+			b.back = val
+			b.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = b.parent
+		}
+		if b.target == val {
+			break
+		}
+		b.target = val
+	case MenuBarItems:
+		if b.menu != nil {
+			b.menu.Close()
+		}
+		b.items = v.([]*MenuItem)
+		b.Update()
+	}
+}
+
+// Capture sets an event function of the menu bar.
+func (b *MenuBar) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		b.closeFn = fn
+	case sparta.Configure:
+		b.configFn = fn
+	case sparta.Command:
+		b.commFn = fn
+	case sparta.Expose:
+		b.exposeFn = fn
+	case sparta.KeyEv:
+		b.keyFn = fn
+	case sparta.Mouse:
+		b.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the menu bar.
+func (b *MenuBar) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if b.closeFn != nil {
+			b.closeFn(b, e)
+		}
+	case sparta.ConfigureEvent:
+		b.geometry = e.(sparta.ConfigureEvent).Rect
+		if b.configFn != nil {
+			b.configFn(b, e)
+		}
+	case sparta.CommandEvent:
+		if b.commFn != nil {
+			if b.commFn(b, e) {
+				return
+			}
+		}
+		b.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if b.exposeFn != nil {
+			b.exposeFn(b, e)
+		}
+		b.win.SetColor(sparta.Foreground, foreColor)
+		for i, it := range b.items {
+			r := b.titleRect(i)
+			fg, bg := foreColor, backColor
+			if it.Disabled {
+				fg = disColor
+			} else if i == b.cur {
+				b.win.Rectangle(r, true)
+				fg, bg = backColor, foreColor
+			}
+			b.win.SetColor(sparta.Foreground, fg)
+			b.win.SetColor(sparta.Background, bg)
+			menuText(b.win, image.Pt(r.Min.X+sparta.WidthUnit, (b.geometry.Dy()-sparta.HeightUnit)/2), it.Caption)
+			b.win.SetColor(sparta.Foreground, foreColor)
+			b.win.SetColor(sparta.Background, backColor)
+		}
+		y := b.geometry.Dy() - 1
+		b.win.Lines([]image.Point{image.Pt(0, y), image.Pt(b.geometry.Dx(), y)})
+	case sparta.KeyEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(b) {
+				return
+			}
+		}
+		if b.keyFn != nil {
+			if b.keyFn(b, e) {
+				return
+			}
+		}
+		ev := e.(sparta.KeyEvent)
+		if b.shortcut(ev) {
+			return
+		}
+		if ev.Key > 0 {
+			b.parent.OnEvent(e)
+		}
+	case sparta.MouseEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(b) {
+				return
+			}
+		}
+		if b.mouseFn != nil {
+			if b.mouseFn(b, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		i := b.titleAt(ev.Loc)
+		switch ev.Button {
+		case 0:
+			if (b.menu != nil) && (i >= 0) && (i != b.cur) {
+				b.open(i, false)
+			}
+		case sparta.MouseLeft:
+			if (i < 0) || ((b.menu != nil) && (i == b.cur)) {
+				if b.menu != nil {
+					b.menu.Close()
+				}
+				return
+			}
+			b.open(i, false)
+		}
+	}
+}
+
+// Open opens the menu of the i-th title. If keyboard is true, the first
+// item of the menu is set as the current item.
+func (b *MenuBar) open(i int, keyboard bool) {
+	it := b.items[i]
+	if it.Disabled {
+		return
+	}
+	if b.menu != nil {
+		b.menu.Close()
+	}
+	if len(it.Sub) == 0 {
+		sparta.SendEvent(b.target, sparta.CommandEvent{Source: b, Value: it.Value})
+		return
+	}
+	r := b.titleRect(i)
+	pt := sparta.RootPos(b).Add(image.Pt(r.Min.X, b.geometry.Dy()))
+	b.cur = i
+	b.menu = NewMenu(b, b.name+"Menu", it.Sub, pt)
+	if keyboard {
+		b.menu.setCur(b.menu.next(-1, 1))
+	}
+	b.Update()
+}
+
+// Step opens the menu of the next title (or the previous title, if d is
+// negative) that has a submenu.
+func (b *MenuBar) step(d int) {
+	n := len(b.items)
+	i := b.cur
+	if (i < 0) && (d < 0) {
+		i = 0
+	}
+	for j := 0; j < n; j++ {
+		i = (i + d + n) % n
+		if !b.items[i].Disabled && (len(b.items[i].Sub) > 0) {
+			b.open(i, true)
+			return
+		}
+	}
+}
+
+// Shortcut opens a menu with the F10 key, or with alt and the mnemonic of
+// a title. It returns true if a menu is opened.
+func (b *MenuBar) shortcut(ev sparta.KeyEvent) bool {
+	if ev.Key == sparta.KeyF10 {
+		b.step(1)
+		return b.menu != nil
+	}
+	if ((ev.State & sparta.StateAlt) == 0) || (ev.Key <= 0) || ((ev.Key & sparta.KeyNoChar) != 0) {
+		return false
+	}
+	r := unicode.ToLower(rune(ev.Key))
+	for i, it := range b.items {
+		if it.Disabled {
+			continue
+		}
+		if _, mn := mnemonic(it.Caption); mn == r {
+			b.open(i, true)
+			return true
+		}
+	}
+	return false
+}
+
+// TitleRect returns the rectangle of the i-th title.
+func (b *MenuBar) titleRect(i int) image.Rectangle {
+	x := 2
+	for j := 0; j < i; j++ {
+		txt, _ := mnemonic(b.items[j].Caption)
+		x += (len(txt) + 2) * sparta.WidthUnit
+	}
+	txt, _ := mnemonic(b.items[i].Caption)
+	return image.Rect(x, 0, x+(len(txt)+2)*sparta.WidthUnit, b.geometry.Dy()-1)
+}
+
+// TitleAt returns the title at the given point, or -1 if there is no
+// title.
+func (b *MenuBar) titleAt(pt image.Point) int {
+	for i := range b.items {
+		if pt.In(b.titleRect(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Update updates the menu bar.
+func (b *MenuBar) Update() {
+	b.win.Update()
+}
+
+// Focus set the focus on the menu bar.
+func (b *MenuBar) Focus() {
+	b.win.Focus()
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/headless"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+// OpenMenu returns the left border of the open menu of a menu bar, or -1
+// if there is no open menu.
+func openMenu(name string) int {
+	for _, w := range headless.Windows() {
+		if m := sparttest.Find(w, name+"Menu"); m != nil {
+			return m.Property(sparta.Geometry).(image.Rectangle).Min.X
+		}
+	}
+	return -1
+}
+
+func TestMenuBarKeys(t *testing.T) {
+	m := widget.NewMainWindow("barMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 300, 200))
+	b := widget.NewMenuBar(m, "bar", image.Rect(0, 0, 300, sparta.HeightUnit+4))
+	b.SetProperty(widget.MenuBarItems, []*widget.MenuItem{
+		{Caption: "&File", Sub: []*widget.MenuItem{
+			{Caption: "&Open", Value: 1},
+			{Caption: "&Save", Value: 2, Disabled: true},
+			{Separator: true},
+			{Caption: "&Recent", Sub: []*widget.MenuItem{
+				{Caption: "a.txt", Value: 10},
+				{Caption: "b.txt", Value: 11},
+			}},
+			{Caption: "&Quit", Value: 3},
+		}},
+		{Caption: "&View", Sub: []*widget.MenuItem{
+			{Caption: "&Wrap", Value: 20},
+		}},
+		{Caption: "&Help", Value: 99},
+	})
+	widget.NewEntry(m, "barEntry", image.Rect(10, 40, 150, 60))
+	tt := sparttest.New(t, m)
+	file := openMenu("bar")
+	if file != -1 {
+		t.Fatalf("menu open at start")
+	}
+
+	// alt and the mnemonic open a menu, from any widget of the window
+	tt.Key("barEntry", sparta.KeyEvent{Key: 'v', State: sparta.StateAlt})
+	view := openMenu("bar")
+	if view < 0 {
+		t.Fatalf("view menu not opened")
+	}
+	tt.Type("barMenu", sparta.KeyLeft)
+	if file = openMenu("bar"); (file < 0) || (file >= view) {
+		t.Fatalf("file menu not opened: %d, view at %d", file, view)
+	}
+	tt.Type("barMenu", sparta.KeyRight)
+	if x := openMenu("bar"); x != view {
+		t.Errorf("menu at %d, want view menu at %d", x, view)
+	}
+
+	// the disabled items and separators are skipped
+	tt.Key("barEntry", sparta.KeyEvent{Key: 'f', State: sparta.StateAlt})
+	tt.Type("barMenu", sparta.KeyDown, sparta.KeyRight)
+	tt.Type("barMenuSub", sparta.KeyDown, sparta.KeyReturn)
+	tt.ExpectCommand("barMain", "bar", 11)
+	if x := openMenu("bar"); x != -1 {
+		t.Errorf("menu open after a selection")
+	}
+
+	// F10 opens the first menu
+	tt.Commands()
+	tt.Type("barEntry", sparta.KeyF10)
+	if x := openMenu("bar"); x != file {
+		t.Errorf("menu at %d, want file menu at %d", x, file)
+	}
+	tt.Type("barMenu", 'q')
+	tt.ExpectCommand("barMain", "bar", 3)
+
+	// escape closes the menu
+	tt.Type("barEntry", sparta.KeyF10)
+	tt.Type("barMenu", sparta.KeyEscape)
+	if x := openMenu("bar"); x != -1 {
+		t.Errorf("menu open after escape")
+	}
+	m.Close()
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/js-arias/sparta"
+)
+
+// Popup is a top level window without decorations, that is shown over
+// the other windows, for example, to show the list of a combo box, or a
+// menu. The geometry of a popup is in screen coordinates (use
+// sparta.RootPos to get the screen position of a widget).
+//
+// Command and key events are sent to the parent of the popup. When the
+// popup grabs the pointer (with sparta.Grab) and a mouse button is pressed
+// outside of it, the popup sends a command event to its parent, with the
+// popup as the source. It is up to the parent to close the popup.
+type Popup struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	childs     []sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	windowState
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// NewPopup creates a new popup, at the given screen position.
+func NewPopup(parent sparta.Widget, name string, rect image.Rectangle) *Popup {
+	p := &Popup{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+	}
+	sparta.NewWindow(p)
+	return p
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// popup.
+func (p *Popup) SetWindow(win sparta.Window) {
+	p.win = win
+}
+
+// Window returns the backend window.
+func (p *Popup) Window() sparta.Window {
+	return p.win
+}
+
+// RemoveWindow removes the backend window.
+func (p *Popup) RemoveWindow() {
+	p.win = nil
+}
+
+// Property returns the indicated property of the popup.
+func (p *Popup) Property(pr sparta.Property) interface{} {
+	switch pr {
+	case sparta.Childs:
+		return p.childs
+	case sparta.Data:
+		return p.data
+	case sparta.Geometry:
+		return p.geometry
+	case sparta.Parent:
+		return p.parent
+	case sparta.Name:
+		return p.name
+	case sparta.Foreground:
+		return p.fore
+	case sparta.Background:
+		return p.back
+	case sparta.Popup:
+		return true
+	}
+	return p.property(pr)
+}
+
+// SetProperty sets a property of the popup.
+func (p *Popup) SetProperty(pr sparta.Property, v interface{}) {
+	if p.setProperty(p.win, pr, v) {
+		return
+	}
+	switch pr {
+	case sparta.Childs:
+		if v == nil {
+			p.childs = nil
+			return
+		}
+		p.childs = append(p.childs, v.(sparta.Widget))
+	case sparta.Data:
+		p.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !p.geometry.Eq(val) {
+			p.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			p.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if p.name != val {
+			p.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if p.fore != val {
+			p.fore = val
+			p.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if p.back != val {
+			p.back = val
+			p.win.SetProperty(sparta.Background, val)
+		}
+	}
+}
+
+// Capture sets an event function of the popup.
+func (p *Popup) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		p.closeFn = fn
+	case sparta.Configure:
+		p.configFn = fn
+	case sparta.Command:
+		p.commFn = fn
+	case sparta.Expose:
+		p.exposeFn = fn
+	case sparta.KeyEv:
+		p.keyFn = fn
+	case sparta.Mouse:
+		p.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the popup.
+func (p *Popup) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if p.closeFn != nil {
+			p.closeFn(p, e)
+		}
+		for _, ch := range p.childs {
+			ch.OnEvent(e)
+		}
+	case sparta.ConfigureEvent:
+		p.geometry = e.(sparta.ConfigureEvent).Rect
+		if p.configFn != nil {
+			p.configFn(p, e)
+		}
+	case sparta.CommandEvent:
+		if p.commFn != nil {
+			if p.commFn(p, e) {
+				return
+			}
+		}
+		if p.parent != nil {
+			p.parent.OnEvent(e)
+		}
+	case sparta.ExposeEvent:
+		if p.exposeFn != nil {
+			p.exposeFn(p, e)
+		}
+		p.win.SetColor(sparta.Foreground, foreColor)
+		rect := image.Rect(0, 0, p.geometry.Dx()-1, p.geometry.Dy()-1)
+		p.win.Rectangle(rect, false)
+	case sparta.KeyEvent:
+		if p.keyFn != nil {
+			if p.keyFn(p, e) {
+				return
+			}
+		}
+		if p.parent != nil {
+			p.parent.OnEvent(e)
+		}
+	case sparta.MouseEvent:
+		if p.mouseFn != nil {
+			if p.mouseFn(p, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		switch ev.Button {
+		case sparta.MouseLeft, sparta.MouseRight, sparta.Mouse2:
+			if ev.Loc.In(image.Rect(0, 0, p.geometry.Dx(), p.geometry.Dy())) {
+				break
+			}
+			if p.parent != nil {
+				sparta.SendEvent(p.parent, sparta.CommandEvent{Source: p})
+			}
+		}
+	}
+}
+
+// Update updates the popup.
+func (p *Popup) Update() {
+	p.win.Update()
+}
+
+// Focus set the focus on the popup.
+func (p *Popup) Focus() {
+	p.win.Focus()
+}
+
+// Close closes the popup.
+func (p *Popup) Close() {
+	p.win.Close()
+}
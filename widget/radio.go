@@ -0,0 +1,349 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/js-arias/sparta"
+)
+
+// RadioGroup is a set of radio buttons in which only one button is
+// checked.
+type RadioGroup struct {
+	buttons []*RadioButton
+	checked *RadioButton
+}
+
+// NewRadioGroup creates a new radio group.
+func NewRadioGroup() *RadioGroup {
+	return &RadioGroup{}
+}
+
+// Checked returns the checked radio button of the group.
+func (g *RadioGroup) Checked() *RadioButton {
+	return g.current()
+}
+
+// Current returns the checked button. If the checked button was removed,
+// the first button of the group is checked.
+func (g *RadioGroup) current() *RadioButton {
+	if (g.checked == nil) && (len(g.buttons) > 0) {
+		g.checked = g.buttons[0]
+	}
+	return g.checked
+}
+
+// Buttons returns the radio buttons of the group.
+func (g *RadioGroup) Buttons() []*RadioButton {
+	return g.buttons
+}
+
+// Check checks a radio button of the group, and unchecks the previous
+// checked button. It returns false if the button was already checked.
+func (g *RadioGroup) check(r *RadioButton) bool {
+	prev := g.current()
+	if prev == r {
+		return false
+	}
+	if prev != nil && prev.win != nil {
+		prev.Update()
+	}
+	g.checked = r
+	if r.win != nil {
+		r.Update()
+	}
+	return true
+}
+
+// Remove removes a radio button from the group. As the button is removed
+// when its window is closed (and possibly, all the buttons of the group
+// are being closed), if it was the checked button, the first button is
+// not checked until the group is used again.
+func (g *RadioGroup) remove(r *RadioButton) {
+	for i, b := range g.buttons {
+		if b != r {
+			continue
+		}
+		g.buttons = append(g.buttons[:i], g.buttons[i+1:]...)
+		break
+	}
+	if g.checked == r {
+		g.checked = nil
+	}
+}
+
+// RadioButton is a widget that shows a text with a mark, that is checked
+// when selected with the mouse, or with the space key. Radio buttons are
+// grouped, and only one button of a group can be checked. When a button is
+// checked, it sends an arbitrary value (that can be set with the property
+// RadioValue) to the target widget.
+//
+// The first button added to a group is checked.
+type RadioButton struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	widgetState
+
+	caption string
+	group   *RadioGroup
+	target  sparta.Widget
+	value   int
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// RadioButton particular properties.
+const (
+	// sets the checked state of the button (bool). Only setting it to
+	// true has effect, as a button is unchecked when other button of
+	// the group is checked.
+	RadioChecked sparta.Property = "checked"
+
+	// sets the value (int) that the button will send to the target
+	// when checked.
+	RadioValue = "value"
+)
+
+// NewRadioButton creates a new radio button, as part of a group.
+func NewRadioButton(parent sparta.Widget, name, caption string, group *RadioGroup, rect image.Rectangle) *RadioButton {
+	r := &RadioButton{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		caption:  caption,
+		group:    group,
+		target:   parent,
+	}
+	sparta.NewWindow(r)
+	group.buttons = append(group.buttons, r)
+	return r
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// radio button.
+func (r *RadioButton) SetWindow(win sparta.Window) {
+	r.win = win
+}
+
+// Window returns the backend window.
+func (r *RadioButton) Window() sparta.Window {
+	return r.win
+}
+
+// RemoveWindow removes the backend window.
+func (r *RadioButton) RemoveWindow() {
+	r.win = nil
+	r.group.remove(r)
+}
+
+// Property returns the indicated property of the radio button.
+func (r *RadioButton) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Caption:
+		return r.caption
+	case sparta.Data:
+		return r.data
+	case sparta.Geometry:
+		return r.geometry
+	case sparta.Parent:
+		return r.parent
+	case sparta.Name:
+		return r.name
+	case sparta.Foreground:
+		return r.fore
+	case sparta.Background:
+		return r.back
+	case sparta.Target:
+		return r.target
+	case RadioChecked:
+		return r.group.current() == r
+	case RadioValue:
+		return r.value
+	case sparta.PrefSize:
+		return image.Pt(12+(len([]rune(r.caption))+2)*sparta.WidthUnit, sparta.HeightUnit+4)
+	}
+	return r.property(p)
+}
+
+// SetProperty sets a property of the radio button.
+func (r *RadioButton) SetProperty(p sparta.Property, v interface{}) {
+	if r.setProperty(r.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Caption:
+		val := v.(string)
+		if r.caption != val {
+			r.caption = val
+			r.Update()
+		}
+	case sparta.Data:
+		r.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !r.geometry.Eq(val) {
+			r.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			r.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if r.name != val {
+			r.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if r.fore != val {
+			r.fore = val
+			r.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if r.back != val {
+			r.back = val
+			r.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = r.parent
+		}
+		if r.target == val {
+			break
+		}
+		r.target = val
+	case RadioChecked:
+		if v.(bool) {
+			r.group.check(r)
+		}
+	case RadioValue:
+		val := v.(int)
+		if r.value != val {
+			r.value = val
+		}
+	}
+}
+
+// Capture sets an event function of the radio button.
+func (r *RadioButton) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		r.closeFn = fn
+	case sparta.Configure:
+		r.configFn = fn
+	case sparta.Command:
+		r.commFn = fn
+	case sparta.Expose:
+		r.exposeFn = fn
+	case sparta.KeyEv:
+		r.keyFn = fn
+	case sparta.Mouse:
+		r.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the radio button.
+func (r *RadioButton) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if r.closeFn != nil {
+			r.closeFn(r, e)
+		}
+	case sparta.ConfigureEvent:
+		r.geometry = e.(sparta.ConfigureEvent).Rect
+		if r.configFn != nil {
+			r.configFn(r, e)
+		}
+	case sparta.CommandEvent:
+		if r.commFn != nil {
+			if r.commFn(r, e) {
+				return
+			}
+		}
+		r.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if r.exposeFn != nil {
+			r.exposeFn(r, e)
+		}
+		r.win.SetColor(sparta.Foreground, foreColor)
+		y := (r.geometry.Dy() - sparta.HeightUnit) / 2
+		mark := image.Rect(2, y+2, 12, y+12)
+		r.win.Arc(mark, 0, 2*math.Pi, false)
+		if r.group.current() == r {
+			r.win.Arc(mark.Inset(3), 0, 2*math.Pi, true)
+		}
+		if len(r.caption) > 0 {
+			r.win.Text(image.Pt(mark.Max.X+sparta.WidthUnit, y), r.caption)
+		}
+	case sparta.KeyEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(r) {
+				return
+			}
+		}
+		if r.keyFn != nil {
+			if r.keyFn(r, e) {
+				return
+			}
+		}
+		ev := e.(sparta.KeyEvent)
+		if ev.Key == ' ' {
+			r.check()
+			return
+		}
+		if ev.Key > 0 {
+			r.parent.OnEvent(e)
+		}
+	case sparta.MouseEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(r) {
+				return
+			}
+		}
+		if r.mouseFn != nil {
+			if r.mouseFn(r, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		if ev.Button == sparta.MouseLeft {
+			r.check()
+		}
+	}
+}
+
+// Check checks the radio button, and if it was unchecked, sends the
+// value to the target.
+func (r *RadioButton) check() {
+	if r.group.check(r) {
+		sparta.SendEvent(r.target, sparta.CommandEvent{Source: r, Value: r.value})
+	}
+}
+
+// Update updates the radio button.
+func (r *RadioButton) Update() {
+	r.win.Update()
+}
+
+// Focus set the focus on the radio button.
+func (r *RadioButton) Focus() {
+	r.win.Focus()
+}
@@ -36,6 +36,7 @@ type Scroll struct {
 	geometry   image.Rectangle
 	fore, back color.RGBA
 	data       interface{}
+	widgetState
 
 	pos, size, page int
 	typ             ScrollType
@@ -116,12 +117,20 @@ func (s *Scroll) Property(p sparta.Property) interface{} {
 		return s.pos
 	case ScrollSize:
 		return s.size
+	case sparta.PrefSize:
+		if s.typ == Vertical {
+			return image.Pt(10, 10*sparta.HeightUnit)
+		}
+		return image.Pt(10*sparta.HeightUnit, 10)
 	}
-	return nil
+	return s.property(p)
 }
 
 // SetProperty sets a property of the scroll.
 func (s *Scroll) SetProperty(p sparta.Property, v interface{}) {
+	if s.setProperty(s.win, p, v) {
+		return
+	}
 	switch p {
 	case sparta.Data:
 		s.data = v
@@ -0,0 +1,448 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/js-arias/sparta"
+)
+
+// ScrollView is a container that shows a part of a child (the content)
+// that can be larger than the scroll view. The content is the first child
+// created in the scroll view. When the content does not fit in the
+// scroll view, vertical and horizontal scrolls are shown, and the content
+// is moved when the scrolls are moved.
+//
+// The size of the content is the size set with SetContentSize, or its
+// preferred size (see sparta.PrefSize), or the size with which it was
+// created. If the content is smaller than the scroll view, it is enlarged
+// to fill the scroll view.
+//
+// The content can be scrolled with the mouse wheel (with the shift key to
+// scroll horizontally), and with the arrow keys, the PageUp and PageDown
+// keys, and the Home and End keys.
+type ScrollView struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	childs     []sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	border     bool
+	data       interface{}
+	widgetState
+
+	vs, hs  *Scroll
+	off     image.Point // position of the content shown at the origin
+	csize   image.Point // size of the content set by the client
+	fixed   bool        // the content size is set with SetContentSize
+	size    image.Point // current size of the content
+	view    image.Point // size of the visible area
+	pending bool
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// ScrollView particular properties.
+const (
+	// sets the position of the content (image.Point) that is shown at
+	// the origin of the scroll view.
+	ScrollViewOffset sparta.Property = "offset"
+)
+
+// scrollWidth is the width of the scrolls of a scroll view.
+const scrollWidth = 10
+
+// NewScrollView creates a new scroll view.
+func NewScrollView(parent sparta.Widget, name string, rect image.Rectangle) *ScrollView {
+	s := &ScrollView{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+	}
+	sparta.NewWindow(s)
+	return s
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// scroll view.
+func (s *ScrollView) SetWindow(win sparta.Window) {
+	s.win = win
+}
+
+// Window returns the backend window.
+func (s *ScrollView) Window() sparta.Window {
+	return s.win
+}
+
+// RemoveWindow removes the backend window.
+func (s *ScrollView) RemoveWindow() {
+	s.win = nil
+}
+
+// Property returns the indicated property of the scroll view.
+func (s *ScrollView) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Childs:
+		return s.childs
+	case sparta.Data:
+		return s.data
+	case sparta.Geometry:
+		return s.geometry
+	case sparta.Parent:
+		return s.parent
+	case sparta.Name:
+		return s.name
+	case sparta.Foreground:
+		return s.fore
+	case sparta.Background:
+		return s.back
+	case sparta.Border:
+		return s.border
+	case ScrollViewOffset:
+		return s.off
+	}
+	return s.property(p)
+}
+
+// SetProperty sets a property of the scroll view.
+func (s *ScrollView) SetProperty(p sparta.Property, v interface{}) {
+	if s.setProperty(s.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Childs:
+		if v == nil {
+			s.childs = nil
+			return
+		}
+		w := v.(sparta.Widget)
+		s.childs = append(s.childs, w)
+
+		// without a preferred size, the initial size is used
+		if (s.content() == nil) && (w.Property(sparta.PrefSize) == nil) {
+			s.csize = w.Property(sparta.Geometry).(image.Rectangle).Size()
+		}
+		s.relayout()
+	case sparta.Data:
+		s.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !s.geometry.Eq(val) {
+			s.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			s.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if s.name != val {
+			s.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if s.fore != val {
+			s.fore = val
+			s.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if s.back != val {
+			s.back = val
+			s.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Border:
+		val := v.(bool)
+		if s.border != val {
+			s.border = val
+			s.Update()
+		}
+	case ScrollViewOffset:
+		s.scrollTo(v.(image.Point))
+	}
+}
+
+// Capture sets an event function of the scroll view.
+func (s *ScrollView) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		s.closeFn = fn
+	case sparta.Configure:
+		s.configFn = fn
+	case sparta.Command:
+		s.commFn = fn
+	case sparta.Expose:
+		s.exposeFn = fn
+	case sparta.KeyEv:
+		s.keyFn = fn
+	case sparta.Mouse:
+		s.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the scroll view.
+func (s *ScrollView) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if s.closeFn != nil {
+			s.closeFn(s, e)
+		}
+		for _, ch := range s.childs {
+			ch.OnEvent(e)
+		}
+	case sparta.ConfigureEvent:
+		s.geometry = e.(sparta.ConfigureEvent).Rect
+		if s.configFn != nil {
+			s.configFn(s, e)
+		}
+		s.Layout()
+	case sparta.CommandEvent:
+		ev := e.(sparta.CommandEvent)
+		switch {
+		case ev.Source == s:
+			if s.pending {
+				s.Layout()
+			}
+			return
+		case (ev.Source == s.vs) && (s.vs != nil):
+			s.off.Y = ev.Value
+			s.place()
+			return
+		case (ev.Source == s.hs) && (s.hs != nil):
+			s.off.X = ev.Value
+			s.place()
+			return
+		}
+		if s.commFn != nil {
+			if s.commFn(s, e) {
+				return
+			}
+		}
+		s.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if s.exposeFn != nil {
+			s.exposeFn(s, e)
+		}
+		if s.border {
+			s.win.SetColor(sparta.Foreground, foreColor)
+			rect := image.Rect(0, 0, s.geometry.Dx()-1, s.geometry.Dy()-1)
+			s.win.Rectangle(rect, false)
+		}
+	case sparta.KeyEvent:
+		if s.keyFn != nil {
+			if s.keyFn(s, e) {
+				return
+			}
+		}
+		ev := e.(sparta.KeyEvent)
+		switch ev.Key {
+		case sparta.KeyUp:
+			s.scrollTo(s.off.Add(image.Pt(0, -sparta.HeightUnit)))
+		case sparta.KeyDown:
+			s.scrollTo(s.off.Add(image.Pt(0, sparta.HeightUnit)))
+		case sparta.KeyLeft:
+			s.scrollTo(s.off.Add(image.Pt(-sparta.WidthUnit, 0)))
+		case sparta.KeyRight:
+			s.scrollTo(s.off.Add(image.Pt(sparta.WidthUnit, 0)))
+		case sparta.KeyPageUp:
+			s.scrollTo(s.off.Add(image.Pt(0, -s.view.Y)))
+		case sparta.KeyPageDown:
+			s.scrollTo(s.off.Add(image.Pt(0, s.view.Y)))
+		case sparta.KeyHome:
+			s.scrollTo(image.Pt(s.off.X, 0))
+		case sparta.KeyEnd:
+			s.scrollTo(image.Pt(s.off.X, s.size.Y))
+		default:
+			s.parent.OnEvent(e)
+		}
+	case sparta.MouseEvent:
+		if s.mouseFn != nil {
+			if s.mouseFn(s, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		step := 3 * sparta.HeightUnit
+		switch ev.Button {
+		case sparta.MouseWheel:
+			if (ev.State & sparta.StateShift) != 0 {
+				s.scrollTo(s.off.Add(image.Pt(-step, 0)))
+			} else {
+				s.scrollTo(s.off.Add(image.Pt(0, -step)))
+			}
+			return
+		case -sparta.MouseWheel:
+			if (ev.State & sparta.StateShift) != 0 {
+				s.scrollTo(s.off.Add(image.Pt(step, 0)))
+			} else {
+				s.scrollTo(s.off.Add(image.Pt(0, step)))
+			}
+			return
+		}
+		ev.Loc = ev.Loc.Add(s.geometry.Min)
+		s.parent.OnEvent(ev)
+	}
+}
+
+// Update updates the scroll view.
+func (s *ScrollView) Update() {
+	s.win.Update()
+}
+
+// Focus set the focus on the scroll view.
+func (s *ScrollView) Focus() {
+	s.win.Focus()
+}
+
+// SetContentSize sets the size of the content, replacing the size
+// reported by the content.
+func (s *ScrollView) SetContentSize(size image.Point) {
+	s.csize = size
+	s.fixed = true
+	s.relayout()
+}
+
+// Content returns the content of the scroll view.
+func (s *ScrollView) content() sparta.Widget {
+	for _, c := range s.childs {
+		if c.Window() == nil {
+			continue
+		}
+		if (c == sparta.Widget(s.vs)) || (c == sparta.Widget(s.hs)) {
+			continue
+		}
+		return c
+	}
+	return nil
+}
+
+// ScrollTo moves the scrolls to show the content at the given position.
+func (s *ScrollView) scrollTo(pt image.Point) {
+	if s.vs == nil {
+		s.off = pt
+		s.relayout()
+		return
+	}
+	s.vs.SetProperty(ScrollPos, pt.Y)
+	s.hs.SetProperty(ScrollPos, pt.X)
+}
+
+// Relayout lays out the scroll view, after all the pending events are
+// processed.
+func (s *ScrollView) relayout() {
+	if s.pending || (s.win == nil) {
+		return
+	}
+	s.pending = true
+	sparta.SendEvent(s, sparta.CommandEvent{Source: s})
+}
+
+// Layout sets the geometry of the content and the scrolls of the scroll
+// view.
+func (s *ScrollView) Layout() {
+	s.pending = false
+	c := s.content()
+	if c == nil {
+		return
+	}
+	s.newScrolls(c)
+
+	size := s.geometry.Size()
+	cs := s.csize
+	if pt, ok := c.Property(sparta.PrefSize).(image.Point); ok && !s.fixed {
+		cs = pt
+	}
+	view := size
+	needV := cs.Y > view.Y
+	if needV {
+		view.X -= scrollWidth
+	}
+	needH := cs.X > view.X
+	if needH {
+		view.Y -= scrollWidth
+		if !needV && (cs.Y > view.Y) {
+			needV = true
+			view.X -= scrollWidth
+		}
+	}
+	if cs.X < view.X {
+		cs.X = view.X
+	}
+	if cs.Y < view.Y {
+		cs.Y = view.Y
+	}
+	s.size, s.view = cs, view
+
+	if s.off.X > cs.X-view.X {
+		s.off.X = cs.X - view.X
+	}
+	if s.off.Y > cs.Y-view.Y {
+		s.off.Y = cs.Y - view.Y
+	}
+	if s.off.X < 0 {
+		s.off.X = 0
+	}
+	if s.off.Y < 0 {
+		s.off.Y = 0
+	}
+
+	s.vs.SetProperty(sparta.Geometry, image.Rect(size.X-scrollWidth, 0, size.X, view.Y))
+	s.vs.SetProperty(ScrollSize, cs.Y)
+	s.vs.SetProperty(ScrollPage, view.Y)
+	s.vs.SetProperty(ScrollPos, s.off.Y)
+	s.vs.SetProperty(sparta.Visible, needV)
+	s.hs.SetProperty(sparta.Geometry, image.Rect(0, size.Y-scrollWidth, view.X, size.Y))
+	s.hs.SetProperty(ScrollSize, cs.X)
+	s.hs.SetProperty(ScrollPage, view.X)
+	s.hs.SetProperty(ScrollPos, s.off.X)
+	s.hs.SetProperty(sparta.Visible, needH)
+	s.place()
+}
+
+// Place sets the geometry of the content.
+func (s *ScrollView) place() {
+	c := s.content()
+	if c == nil {
+		return
+	}
+	min := image.Pt(-s.off.X, -s.off.Y)
+	c.SetProperty(sparta.Geometry, image.Rectangle{Min: min, Max: min.Add(s.size)})
+}
+
+// NewScrolls creates the scrolls of the scroll view. As the windows
+// created later are shown over the previous ones, the scrolls are created
+// again if they are older than the content.
+func (s *ScrollView) newScrolls(c sparta.Widget) {
+	if s.vs != nil {
+		old := true
+		for _, ch := range s.childs {
+			if ch == c {
+				old = false
+				break
+			}
+			if ch == sparta.Widget(s.vs) {
+				break
+			}
+		}
+		if !old {
+			return
+		}
+		s.vs.Window().Close()
+		s.hs.Window().Close()
+	}
+	r := image.Rect(0, 0, scrollWidth, scrollWidth)
+	s.vs = NewScroll(s, "scrollView"+s.name+"VScroll", 0, 0, Vertical, r)
+	s.hs = NewScroll(s, "scrollView"+s.name+"HScroll", 0, 0, Horizontal, r)
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func TestScrollView(t *testing.T) {
+	m := widget.NewMainWindow("viewMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 150))
+	widget.NewScrollView(m, "view", image.Rect(0, 0, 200, 150))
+	c := widget.NewCanvas(sparttest.Find(m, "view"), "viewCanvas", image.Rect(0, 0, 300, 600))
+	tt := sparttest.New(t, m)
+	expectGeometry(t, c, image.Rect(0, 0, 300, 600))
+	tt.Mouse("viewCanvas", sparta.MouseEvent{Button: -sparta.MouseWheel, Loc: image.Pt(5, 5)})
+	expectGeometry(t, c, image.Rect(0, -45, 300, 555))
+	m.Close()
+}
+
+// The content size set by the client is kept, even if the content has a
+// preferred size.
+func TestScrollViewContentSize(t *testing.T) {
+	m := widget.NewMainWindow("viewSizeMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 200))
+	sv := widget.NewScrollView(m, "viewSize", image.Rect(0, 0, 200, 200))
+	b := widget.NewVBox(sv, "viewSizeBox", image.Rect(0, 0, 100, 100))
+	sv.SetContentSize(image.Pt(1000, 1000))
+	sparttest.New(t, m)
+	if g := b.Property(sparta.Geometry).(image.Rectangle); g.Size() != image.Pt(1000, 1000) {
+		t.Errorf("content size %v, want %v", g.Size(), image.Pt(1000, 1000))
+	}
+	m.Close()
+}
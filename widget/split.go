@@ -0,0 +1,454 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/js-arias/sparta"
+)
+
+// Split is a container with two panes, side by side (an horizontal split),
+// or stacked (a vertical split), separated by a sash that can be dragged
+// with the mouse. The panes are the first two children created in the
+// split.
+//
+// When the sash is clicked, its position can be changed with the arrow
+// keys, the Home and End keys move the sash to its minimum and maximum
+// positions.
+type Split struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	childs     []sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	border     bool
+	data       interface{}
+	widgetState
+
+	vertical bool
+	pos      int
+	mins     map[sparta.Widget]int
+	drag     int
+	active   bool
+	pending  bool
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// Split particular properties.
+const (
+	// sets the position of the sash (int), as the size of the first
+	// pane. If negative, the sash is put at the middle of the split.
+	SplitPos sparta.Property = "position"
+)
+
+// sashSize is the width of the sash.
+const sashSize = 6
+
+// NewHSplit creates a new horizontal split.
+func NewHSplit(parent sparta.Widget, name string, rect image.Rectangle) *Split {
+	return newSplit(parent, name, false, rect)
+}
+
+// NewVSplit creates a new vertical split.
+func NewVSplit(parent sparta.Widget, name string, rect image.Rectangle) *Split {
+	return newSplit(parent, name, true, rect)
+}
+
+// NewSplit creates a new split.
+func newSplit(parent sparta.Widget, name string, vertical bool, rect image.Rectangle) *Split {
+	s := &Split{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		vertical: vertical,
+		pos:      -1,
+		mins:     make(map[sparta.Widget]int),
+		drag:     -1,
+	}
+	sparta.NewWindow(s)
+	return s
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// split.
+func (s *Split) SetWindow(win sparta.Window) {
+	s.win = win
+}
+
+// Window returns the backend window.
+func (s *Split) Window() sparta.Window {
+	return s.win
+}
+
+// RemoveWindow removes the backend window.
+func (s *Split) RemoveWindow() {
+	s.win = nil
+}
+
+// Property returns the indicated property of the split.
+func (s *Split) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Childs:
+		return s.childs
+	case sparta.Data:
+		return s.data
+	case sparta.Geometry:
+		return s.geometry
+	case sparta.Parent:
+		return s.parent
+	case sparta.Name:
+		return s.name
+	case sparta.Foreground:
+		return s.fore
+	case sparta.Background:
+		return s.back
+	case sparta.Border:
+		return s.border
+	case sparta.PrefSize:
+		return s.prefSize()
+	case SplitPos:
+		if s.pos < 0 {
+			return s.clamp(s.pos)
+		}
+		return s.pos
+	}
+	return s.property(p)
+}
+
+// SetProperty sets a property of the split.
+func (s *Split) SetProperty(p sparta.Property, v interface{}) {
+	if s.setProperty(s.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Childs:
+		if v == nil {
+			s.childs = nil
+			return
+		}
+		s.childs = append(s.childs, v.(sparta.Widget))
+		s.relayout()
+	case sparta.Data:
+		s.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !s.geometry.Eq(val) {
+			s.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			s.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if s.name != val {
+			s.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if s.fore != val {
+			s.fore = val
+			s.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if s.back != val {
+			s.back = val
+			s.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Border:
+		val := v.(bool)
+		if s.border != val {
+			s.border = val
+			s.Update()
+		}
+	case SplitPos:
+		val := v.(int)
+		if s.pos != val {
+			s.pos = val
+			s.relayout()
+		}
+	}
+}
+
+// Capture sets an event function of the split.
+func (s *Split) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		s.closeFn = fn
+	case sparta.Configure:
+		s.configFn = fn
+	case sparta.Command:
+		s.commFn = fn
+	case sparta.Expose:
+		s.exposeFn = fn
+	case sparta.KeyEv:
+		s.keyFn = fn
+	case sparta.Mouse:
+		s.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the split.
+func (s *Split) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if s.closeFn != nil {
+			s.closeFn(s, e)
+		}
+		for _, ch := range s.childs {
+			ch.OnEvent(e)
+		}
+	case sparta.ConfigureEvent:
+		s.geometry = e.(sparta.ConfigureEvent).Rect
+		if s.configFn != nil {
+			s.configFn(s, e)
+		}
+		s.Layout()
+	case sparta.CommandEvent:
+		ev := e.(sparta.CommandEvent)
+		if ev.Source == s {
+			if s.pending {
+				s.Layout()
+			}
+			return
+		}
+		if s.commFn != nil {
+			if s.commFn(s, e) {
+				return
+			}
+		}
+		s.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if s.exposeFn != nil {
+			s.exposeFn(s, e)
+		}
+		s.win.SetColor(sparta.Foreground, foreColor)
+		pos := s.clamp(s.pos)
+		if s.vertical {
+			y := pos + sashSize/2
+			s.win.Lines([]image.Point{image.Pt(2, y), image.Pt(s.geometry.Dx()-3, y)})
+		} else {
+			x := pos + sashSize/2
+			s.win.Lines([]image.Point{image.Pt(x, 2), image.Pt(x, s.geometry.Dy()-3)})
+		}
+		if s.border {
+			rect := image.Rect(0, 0, s.geometry.Dx()-1, s.geometry.Dy()-1)
+			s.win.Rectangle(rect, false)
+		}
+	case sparta.KeyEvent:
+		if s.keyFn != nil {
+			if s.keyFn(s, e) {
+				return
+			}
+		}
+		if s.active && s.moveKey(e.(sparta.KeyEvent)) {
+			return
+		}
+		s.parent.OnEvent(e)
+	case sparta.MouseEvent:
+		if s.mouseFn != nil {
+			if s.mouseFn(s, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		switch {
+		case ev.Button == sparta.MouseLeft:
+			pos := s.clamp(s.pos)
+			at := s.along(ev.Loc)
+			if (at >= pos) && (at < pos+sashSize) {
+				s.drag = at - pos
+				s.active = true
+				sparta.Grab(s)
+				return
+			}
+			s.active = false
+		case ev.Button == -sparta.MouseLeft:
+			if s.drag >= 0 {
+				s.drag = -1
+				sparta.Ungrab()
+				return
+			}
+		case (ev.Button == 0) && (s.drag >= 0):
+			if (ev.State & sparta.StateButtonL) == 0 {
+				s.drag = -1
+				sparta.Ungrab()
+				break
+			}
+			s.SetProperty(SplitPos, s.clamp(s.along(ev.Loc)-s.drag))
+			return
+		case ev.Button > 0:
+			s.active = false
+		}
+		ev.Loc = ev.Loc.Add(s.geometry.Min)
+		s.parent.OnEvent(ev)
+	}
+}
+
+// Update updates the split.
+func (s *Split) Update() {
+	s.win.Update()
+}
+
+// Focus set the focus on the split.
+func (s *Split) Focus() {
+	s.win.Focus()
+}
+
+// SetMinSize sets the minimum size of a pane of the split, along the axis
+// of the split.
+func (s *Split) SetMinSize(w sparta.Widget, size int) {
+	s.mins[w] = size
+	s.relayout()
+}
+
+// MoveKey moves the sash with the keyboard. It returns true if the key
+// was used.
+func (s *Split) moveKey(ev sparta.KeyEvent) bool {
+	step := sparta.WidthUnit
+	var dec, inc sparta.Key = sparta.KeyLeft, sparta.KeyRight
+	if s.vertical {
+		step = sparta.HeightUnit
+		dec, inc = sparta.KeyUp, sparta.KeyDown
+	}
+	if step < 1 {
+		step = 1
+	}
+	pos := s.clamp(s.pos)
+	switch ev.Key {
+	case dec:
+		pos -= step
+	case inc:
+		pos += step
+	case sparta.KeyHome:
+		pos = 0
+	case sparta.KeyEnd:
+		pos = s.along(s.geometry.Size())
+	default:
+		return false
+	}
+	s.SetProperty(SplitPos, s.clamp(pos))
+	return true
+}
+
+// Panes returns the panes of the split.
+func (s *Split) panes() []sparta.Widget {
+	var ws []sparta.Widget
+	for _, c := range s.childs {
+		if c.Window() == nil {
+			continue
+		}
+		ws = append(ws, c)
+		if len(ws) == 2 {
+			break
+		}
+	}
+	return ws
+}
+
+// Clamp returns a valid position of the sash, using the minimum sizes of
+// the panes. If there is not enough space, the first pane keeps its
+// minimum size.
+func (s *Split) clamp(pos int) int {
+	size := s.along(s.geometry.Size()) - sashSize
+	if pos < 0 {
+		pos = size / 2
+	}
+	ws := s.panes()
+	if len(ws) > 1 {
+		if max := size - s.mins[ws[1]]; pos > max {
+			pos = max
+		}
+	}
+	if len(ws) > 0 {
+		if min := s.mins[ws[0]]; pos < min {
+			pos = min
+		}
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	return pos
+}
+
+// Relayout lays out the panes of the split, after all the pending events
+// are processed.
+func (s *Split) relayout() {
+	if s.pending || (s.win == nil) {
+		return
+	}
+	s.pending = true
+	sparta.SendEvent(s, sparta.CommandEvent{Source: s})
+}
+
+// Layout sets the geometry of the panes of the split.
+func (s *Split) Layout() {
+	s.pending = false
+	pos := s.clamp(s.pos)
+	size := s.geometry.Size()
+	for i, c := range s.panes() {
+		r := image.Rect(0, 0, pos, size.Y)
+		if i > 0 {
+			r = image.Rect(pos+sashSize, 0, size.X, size.Y)
+		}
+		if s.vertical {
+			r = image.Rect(0, 0, size.X, pos)
+			if i > 0 {
+				r = image.Rect(0, pos+sashSize, size.X, size.Y)
+			}
+		}
+		c.SetProperty(sparta.Geometry, r)
+	}
+	s.win.Update()
+}
+
+// PrefSize returns the preferred size of the split, from the preferred
+// size of its panes.
+func (s *Split) prefSize() image.Point {
+	along, cross := sashSize, 0
+	for _, c := range s.panes() {
+		pref := prefSize(c)
+		if m := s.mins[c]; s.along(pref) < m {
+			along += m
+		} else {
+			along += s.along(pref)
+		}
+		if cr := s.across(pref); cr > cross {
+			cross = cr
+		}
+	}
+	if s.vertical {
+		return image.Pt(cross, along)
+	}
+	return image.Pt(along, cross)
+}
+
+// Along returns the size along the axis of the split.
+func (s *Split) along(pt image.Point) int {
+	if s.vertical {
+		return pt.Y
+	}
+	return pt.X
+}
+
+// Across returns the size across the axis of the split.
+func (s *Split) across(pt image.Point) int {
+	if s.vertical {
+		return pt.X
+	}
+	return pt.Y
+}
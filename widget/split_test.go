@@ -0,0 +1,52 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func TestSplit(t *testing.T) {
+	m := widget.NewMainWindow("splitMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 300, 200))
+	s := widget.NewHSplit(m, "split", image.Rect(0, 0, 300, 200))
+	l := widget.NewList(s, "splitList", image.Rect(0, 0, 1, 1))
+	c := widget.NewCanvas(s, "splitCanvas", image.Rect(0, 0, 1, 1))
+	s.SetMinSize(l, 50)
+	s.SetMinSize(c, 80)
+	s.SetProperty(widget.SplitPos, 100)
+	tt := sparttest.New(t, m)
+	expectGeometry(t, l, image.Rect(0, 0, 100, 200))
+	expectGeometry(t, c, image.Rect(106, 0, 300, 200))
+
+	// the drag continues when the pointer leaves the sash
+	tt.Mouse("split", sparta.MouseEvent{Button: sparta.MouseLeft, Loc: image.Pt(102, 50)})
+	tt.Mouse("splitCanvas", sparta.MouseEvent{State: sparta.StateButtonL, Loc: image.Pt(54, 50)})
+	tt.Mouse("splitCanvas", sparta.MouseEvent{Button: -sparta.MouseLeft, Loc: image.Pt(54, 50)})
+	if p := s.Property(widget.SplitPos).(int); p != 158 {
+		t.Errorf("position %d, want 158", p)
+	}
+	tt.Click("splitCanvas", sparta.MouseLeft, image.Pt(5, 5))
+	if p := s.Property(widget.SplitPos).(int); p != 158 {
+		t.Errorf("position %d after the drag, want 158", p)
+	}
+
+	// the keys move an active sash, keeping the minimum sizes
+	tt.Type("split", sparta.KeyHome)
+	if p := s.Property(widget.SplitPos).(int); p != 158 {
+		t.Errorf("position %d with an inactive sash, want 158", p)
+	}
+	tt.Click("split", sparta.MouseLeft, image.Pt(160, 50))
+	tt.Type("split", sparta.KeyHome)
+	expectGeometry(t, l, image.Rect(0, 0, 50, 200))
+	tt.Type("split", sparta.KeyEnd)
+	expectGeometry(t, c, image.Rect(220, 0, 300, 200))
+	m.Close()
+}
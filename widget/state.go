@@ -0,0 +1,82 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import "github.com/js-arias/sparta"
+
+// windowState keeps the visibility and the state (enabled or disabled) of
+// a widget. It is embedded in the widgets, that pass to it the properties
+// that they do not handle.
+//
+// The backends do not send input events to a disabled widget, or to a
+// widget with a disabled ancestor, so the widgets do not check its state
+// when processing an event.
+type windowState struct {
+	hidden   bool
+	disabled bool
+}
+
+// Property returns a shared property, or nil if p is not a shared
+// property.
+func (s *windowState) property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Visible:
+		return !s.hidden
+	case sparta.Enabled:
+		return !s.disabled
+	}
+	return nil
+}
+
+// SetProperty sets a shared property, and updates the backend window of
+// the widget. It returns false if p is not a shared property.
+func (s *windowState) setProperty(win sparta.Window, p sparta.Property, v interface{}) bool {
+	switch p {
+	case sparta.Visible:
+		val := !v.(bool)
+		if s.hidden != val {
+			s.hidden = val
+			win.SetProperty(sparta.Visible, !val)
+		}
+	case sparta.Enabled:
+		val := !v.(bool)
+		if s.disabled != val {
+			s.disabled = val
+			win.SetProperty(sparta.Enabled, !val)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// widgetState keeps the properties shared by the widgets that are
+// children of other widgets.
+type widgetState struct {
+	windowState
+	anchors Anchors
+}
+
+// Property returns a shared property, or nil if p is not a shared
+// property.
+func (s *widgetState) property(p sparta.Property) interface{} {
+	switch p {
+	case Anchor:
+		return s.anchors
+	}
+	return s.windowState.property(p)
+}
+
+// SetProperty sets a shared property. It returns false if p is not a
+// shared property.
+func (s *widgetState) setProperty(win sparta.Window, p sparta.Property, v interface{}) bool {
+	switch p {
+	case Anchor:
+		s.anchors = v.(Anchors)
+	default:
+		return s.windowState.setProperty(win, p, v)
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func TestState(t *testing.T) {
+	m := widget.NewMainWindow("stateMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 100))
+	c := widget.NewCanvas(m, "stateCanvas", image.Rect(0, 0, 200, 100))
+	c.Capture(sparta.Expose, func(w sparta.Widget, e interface{}) bool {
+		w.(*widget.Canvas).Draw(widget.Rectangle{Rect: image.Rect(100, 50, 120, 70), Fill: true})
+		return false
+	})
+	b := widget.NewButton(c, "stateButton", "OK", image.Rect(10, 10, 60, 30))
+	b.SetProperty(widget.ButtonValue, 1)
+	tt := sparttest.New(t, m)
+
+	click := func() int {
+		tt.Commands()
+		tt.Click("stateButton", sparta.MouseLeft, image.Pt(5, 5))
+		return len(tt.Commands())
+	}
+	if n := click(); n != 1 {
+		t.Fatalf("%d commands from an enabled button, want 1", n)
+	}
+
+	b.SetProperty(sparta.Enabled, false)
+	if b.Property(sparta.Enabled).(bool) {
+		t.Errorf("button enabled")
+	}
+	if n := click(); n != 0 {
+		t.Errorf("%d commands from a disabled button", n)
+	}
+	b.SetProperty(sparta.Enabled, true)
+
+	// the children of a disabled widget are also disabled, and are drawn
+	// with grayed colors
+	c.SetProperty(sparta.Enabled, false)
+	if n := click(); n != 0 {
+		t.Errorf("%d commands from the child of a disabled widget", n)
+	}
+	tt.ExpectPixel("stateCanvas", image.Pt(110, 60), color.RGBA{R: 128, G: 128, B: 128, A: 255})
+	c.SetProperty(sparta.Enabled, true)
+	tt.Idle()
+	tt.ExpectPixel("stateCanvas", image.Pt(110, 60), color.RGBA{A: 255})
+
+	b.SetProperty(sparta.Visible, false)
+	if b.Property(sparta.Visible).(bool) {
+		t.Errorf("button visible")
+	}
+	if n := click(); n != 0 {
+		t.Errorf("%d commands from a hidden button", n)
+	}
+	m.Close()
+}
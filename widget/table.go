@@ -0,0 +1,653 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/js-arias/sparta"
+)
+
+// TableData is a table used by a table control.
+type TableData interface {
+	// Rows returns the number of rows of the table.
+	Rows() int
+
+	// Cols returns the number of columns of the table.
+	Cols() int
+
+	// Title returns the title of the i-th column.
+	Title(int) string
+
+	// Cell returns the text of a cell.
+	Cell(row, col int) string
+
+	// IsSel returns true if a cell is selected.
+	IsSel(row, col int) bool
+}
+
+// Table is a widget that shows a table of strings, with a header with
+// the titles of the columns. The width of a column can be changed
+// dragging the right border of its title. Only the visible cells are
+// read from the table, so the table can be very large.
+//
+// When a cell is selected with the mouse (or with the return or space
+// keys in the cell of the cursor, that is moved with the arrow keys), it
+// sends a command event to its target widget indicating the row of the
+// selected cell. It will be a positive number if the selection is made
+// with the left button or negative (starting at -1), if it was with the
+// right button. The column of the selected cell is stored in the property
+// TableCol. When the title of a column is clicked, the table sends a
+// command event, from its header (see the property TableHeader),
+// indicating the column, so the client code can sort the table.
+//
+// It is up to client code to manage the selection, and the sorting, of the
+// table.
+type Table struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	widgetState
+
+	table  TableData
+	widths []int
+	rowSel bool
+	row    int // cursor row
+	col    int // cursor column
+	selCol int
+	resize int // column resized with the mouse
+	target sparta.Widget
+	header *Canvas
+	vs, hs *Scroll
+	childs []sparta.Widget
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// Table particular properties.
+const (
+	// sets the table (TableData)
+	TableTable sparta.Property = "table"
+
+	// sets the row selection mode (bool). In row selection mode the
+	// cursor is a whole row.
+	TableRowSel = "rowsel"
+
+	// column (int) of the last selected cell. It is read only.
+	TableCol = "col"
+
+	// header widget (sparta.Widget) of the table, that is the source of
+	// the column title events. It is read only.
+	TableHeader = "header"
+)
+
+// Table dimensions.
+const (
+	minColWidth = 10
+	tableScroll = 10
+)
+
+// NewTable creates a new table.
+func NewTable(parent sparta.Widget, name string, rect image.Rectangle) *Table {
+	t := &Table{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		resize:   -1,
+		target:   parent,
+	}
+	sparta.NewWindow(t)
+	hr, vr, sr := t.parts()
+	t.header = NewCanvas(t, "table"+name+"Header", hr)
+	t.header.Capture(sparta.Expose, t.headerExpose)
+	t.header.Capture(sparta.Mouse, t.headerMouse)
+	t.vs = NewScroll(t, "table"+name+"VScroll", 0, 0, Vertical, vr)
+	t.hs = NewScroll(t, "table"+name+"HScroll", 0, 0, Horizontal, sr)
+	return t
+}
+
+// SetWindow is used by the backend to sets the backend window of the table.
+func (t *Table) SetWindow(win sparta.Window) {
+	t.win = win
+}
+
+// Window returns the backend window.
+func (t *Table) Window() sparta.Window {
+	return t.win
+}
+
+// RemoveWindow removes the backend window.
+func (t *Table) RemoveWindow() {
+	t.win = nil
+}
+
+// Property returns the indicated property of the table.
+func (t *Table) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Childs:
+		return t.childs
+	case sparta.Data:
+		return t.data
+	case sparta.Geometry:
+		return t.geometry
+	case sparta.Parent:
+		return t.parent
+	case sparta.Name:
+		return t.name
+	case sparta.Foreground:
+		return t.fore
+	case sparta.Background:
+		return t.back
+	case sparta.Target:
+		return t.target
+	case TableTable:
+		return t.table
+	case TableRowSel:
+		return t.rowSel
+	case TableCol:
+		return t.selCol
+	case TableHeader:
+		return t.header
+	case sparta.PrefSize:
+		return image.Pt(40*sparta.WidthUnit+tableScroll, 10*sparta.HeightUnit+tableScroll)
+	}
+	return t.property(p)
+}
+
+// SetProperty sets a property of the table.
+func (t *Table) SetProperty(p sparta.Property, v interface{}) {
+	if t.setProperty(t.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Childs:
+		if v == nil {
+			t.childs = nil
+			return
+		}
+		t.childs = append(t.childs, v.(sparta.Widget))
+	case sparta.Data:
+		t.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !t.geometry.Eq(val) {
+			t.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			t.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if t.name != val {
+			t.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if t.fore != val {
+			t.fore = val
+			t.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if t.back != val {
+			t.back = val
+			t.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = t.parent
+		}
+		if t.target == val {
+			break
+		}
+		t.target = val
+	case TableTable:
+		if v == nil {
+			t.table = nil
+		} else {
+			t.table = v.(TableData)
+		}
+		t.vs.SetProperty(ScrollSize, 0)
+		t.hs.SetProperty(ScrollSize, 0)
+		t.setScrolls()
+		t.Update()
+		t.header.Update()
+	case TableRowSel:
+		val := v.(bool)
+		if t.rowSel != val {
+			t.rowSel = val
+			t.Update()
+		}
+	}
+}
+
+// Capture sets an event function of the table.
+func (t *Table) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		t.closeFn = fn
+	case sparta.Configure:
+		t.configFn = fn
+	case sparta.Command:
+		t.commFn = fn
+	case sparta.Expose:
+		t.exposeFn = fn
+	case sparta.KeyEv:
+		t.keyFn = fn
+		t.vs.Capture(e, fn)
+		t.hs.Capture(e, fn)
+	case sparta.Mouse:
+		t.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the table.
+func (t *Table) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if t.closeFn != nil {
+			t.closeFn(t, e)
+		}
+		for _, ch := range t.childs {
+			ch.OnEvent(e)
+		}
+	case sparta.ConfigureEvent:
+		t.geometry = e.(sparta.ConfigureEvent).Rect
+		if t.configFn != nil {
+			t.configFn(t, e)
+		}
+		hr, vr, sr := t.parts()
+		t.header.SetProperty(sparta.Geometry, hr)
+		t.vs.SetProperty(sparta.Geometry, vr)
+		t.hs.SetProperty(sparta.Geometry, sr)
+		t.setScrolls()
+	case sparta.CommandEvent:
+		if t.commFn != nil {
+			if t.commFn(t, e) {
+				return
+			}
+		}
+		ev := e.(sparta.CommandEvent)
+		if ev.Source == t.vs {
+			t.Update()
+			return
+		}
+		if ev.Source == t.hs {
+			t.Update()
+			t.header.Update()
+			return
+		}
+		t.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if t.exposeFn != nil {
+			t.exposeFn(t, e)
+		}
+		t.draw()
+	case sparta.KeyEvent:
+		if t.keyFn != nil {
+			if t.keyFn(t, e) {
+				return
+			}
+		}
+		page := t.page()
+		ev := e.(sparta.KeyEvent)
+		switch ev.Key {
+		case sparta.KeyDown:
+			t.setCursor(t.row+1, t.col)
+		case sparta.KeyUp:
+			t.setCursor(t.row-1, t.col)
+		case sparta.KeyLeft:
+			t.setCursor(t.row, t.col-1)
+		case sparta.KeyRight:
+			t.setCursor(t.row, t.col+1)
+		case sparta.KeyPageUp:
+			t.setCursor(t.row-page, t.col)
+		case sparta.KeyPageDown:
+			t.setCursor(t.row+page, t.col)
+		case sparta.KeyHome:
+			t.setCursor(0, t.col)
+		case sparta.KeyEnd:
+			t.setCursor(t.rows()-1, t.col)
+		case ' ', sparta.KeyReturn, sparta.KeyPadEnter:
+			if (t.row < t.rows()) && (t.col < t.cols()) {
+				t.selCol = t.col
+				sparta.SendEvent(t.target, sparta.CommandEvent{Source: t, Value: t.row})
+			}
+		default:
+			t.parent.OnEvent(e)
+		}
+	case sparta.MouseEvent:
+		if t.mouseFn != nil {
+			if t.mouseFn(t, e) {
+				return
+			}
+		}
+		pos, _ := t.offset()
+		ev := e.(sparta.MouseEvent)
+		switch ev.Button {
+		case -sparta.MouseWheel:
+			t.vs.SetProperty(ScrollPos, pos+1)
+		case sparta.MouseWheel:
+			t.vs.SetProperty(ScrollPos, pos-1)
+		case sparta.MouseLeft, sparta.MouseRight:
+			row := ((ev.Loc.Y - t.headerHeight()) / sparta.HeightUnit) + pos
+			col := t.colAt(ev.Loc.X)
+			if (ev.Loc.Y < t.headerHeight()) || (row >= t.rows()) || (col < 0) {
+				break
+			}
+			t.row, t.col, t.selCol = row, col, col
+			t.Update()
+			if ev.Button == sparta.MouseRight {
+				row = -(row + 1)
+			}
+			sparta.SendEvent(t.target, sparta.CommandEvent{Source: t, Value: row})
+		}
+	}
+}
+
+// Update updates the table.
+func (t *Table) Update() {
+	t.win.Update()
+}
+
+// Focus set the focus on the table.
+func (t *Table) Focus() {
+	t.win.Focus()
+}
+
+// SetColWidth sets the width of a column of the table.
+func (t *Table) SetColWidth(col, width int) {
+	if (col < 0) || (col >= t.cols()) {
+		return
+	}
+	if width < minColWidth {
+		width = minColWidth
+	}
+	t.width(col)
+	t.widths[col] = width
+	t.setScrolls()
+	t.Update()
+	t.header.Update()
+}
+
+// Rows returns the number of rows of the table.
+func (t *Table) rows() int {
+	if t.table == nil {
+		return 0
+	}
+	return t.table.Rows()
+}
+
+// Cols returns the number of columns of the table.
+func (t *Table) cols() int {
+	if t.table == nil {
+		return 0
+	}
+	return t.table.Cols()
+}
+
+// Width returns the width of a column. By default the width of a column
+// is set from the length of its title.
+func (t *Table) width(col int) int {
+	for len(t.widths) <= col {
+		w := (len(t.table.Title(len(t.widths))) + 2) * sparta.WidthUnit
+		if w < 10*sparta.WidthUnit {
+			w = 10 * sparta.WidthUnit
+		}
+		t.widths = append(t.widths, w)
+	}
+	return t.widths[col]
+}
+
+// TotalWidth returns the width of all the columns of the table.
+func (t *Table) totalWidth() int {
+	w := 0
+	for i, n := 0, t.cols(); i < n; i++ {
+		w += t.width(i)
+	}
+	return w
+}
+
+// ColAt returns the column at a given position of the table. It returns
+// -1 if there is no column at that position.
+func (t *Table) colAt(x int) int {
+	_, hpos := t.offset()
+	x += hpos
+	for i, n := 0, t.cols(); i < n; i++ {
+		w := t.width(i)
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	return -1
+}
+
+// ColPos returns the position of a column in the table, without
+// scrolling.
+func (t *Table) colPos(col int) int {
+	x := 0
+	for i := 0; i < col; i++ {
+		x += t.width(i)
+	}
+	return x
+}
+
+// HeaderHeight returns the height of the header of the table.
+func (t *Table) headerHeight() int {
+	return sparta.HeightUnit + 4
+}
+
+// Offset returns the first visible row, and the horizontal scrolling of
+// the table.
+func (t *Table) offset() (row, x int) {
+	row = t.vs.Property(ScrollPos).(int)
+	if row < 0 {
+		row = 0
+	}
+	x = t.hs.Property(ScrollPos).(int)
+	if x < 0 {
+		x = 0
+	}
+	return row, x
+}
+
+// Page returns the number of visible rows.
+func (t *Table) page() int {
+	return (t.geometry.Dy() - t.headerHeight() - tableScroll) / sparta.HeightUnit
+}
+
+// Parts returns the geometry of the header, and the vertical and
+// horizontal scrolls.
+func (t *Table) parts() (header, vs, hs image.Rectangle) {
+	w, h := t.geometry.Dx(), t.geometry.Dy()
+	hh := t.headerHeight()
+	header = image.Rect(0, 0, w-tableScroll, hh)
+	vs = image.Rect(w-tableScroll, hh, w, h-tableScroll)
+	hs = image.Rect(0, h-tableScroll, w-tableScroll, h)
+	return
+}
+
+// SetScrolls sets the size of the scrolls.
+func (t *Table) setScrolls() {
+	t.vs.SetProperty(ScrollSize, t.rows())
+	t.vs.SetProperty(ScrollPage, t.page())
+	t.hs.SetProperty(ScrollSize, t.totalWidth())
+	t.hs.SetProperty(ScrollPage, t.geometry.Dx()-tableScroll)
+}
+
+// SetCursor moves the cursor to a cell, and scrolls the table to show
+// it.
+func (t *Table) setCursor(row, col int) {
+	if row >= t.rows() {
+		row = t.rows() - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+	if col >= t.cols() {
+		col = t.cols() - 1
+	}
+	if col < 0 {
+		col = 0
+	}
+	t.row, t.col = row, col
+	pos, hpos := t.offset()
+	page := t.page()
+	if row < pos {
+		t.vs.SetProperty(ScrollPos, row)
+	} else if (page > 0) && (row >= pos+page) {
+		t.vs.SetProperty(ScrollPos, row-page+1)
+	}
+	if !t.rowSel && (t.cols() > 0) {
+		x := t.colPos(col)
+		vw := t.geometry.Dx() - tableScroll
+		if x < hpos {
+			t.hs.SetProperty(ScrollPos, x)
+		} else if x+t.width(col) > hpos+vw {
+			t.hs.SetProperty(ScrollPos, x+t.width(col)-vw)
+		}
+	}
+	t.Update()
+}
+
+// Draw draws the visible cells of the table.
+func (t *Table) draw() {
+	t.win.SetColor(sparta.Foreground, foreColor)
+	vw := t.geometry.Dx() - tableScroll
+	vh := t.geometry.Dy() - tableScroll
+	hh := t.headerHeight()
+	if t.table != nil {
+		pos, hpos := t.offset()
+		rows, cols := t.rows(), t.cols()
+		for i, page := 0, t.page(); i <= page; i++ {
+			row := pos + i
+			if row >= rows {
+				break
+			}
+			y := hh + (i * sparta.HeightUnit)
+			x := -hpos
+			for col := 0; (col < cols) && (x < vw); col++ {
+				w := t.width(col)
+				if x+w <= 0 {
+					x += w
+					continue
+				}
+				r := image.Rect(x, y, x+w-1, y+sparta.HeightUnit)
+				sel := t.table.IsSel(row, col)
+				if sel {
+					t.win.Rectangle(r, true)
+					t.win.SetColor(sparta.Foreground, backColor)
+					t.win.SetColor(sparta.Background, foreColor)
+				}
+				t.win.Text(image.Pt(x+2, y+2), cellText(t.table.Cell(row, col), w))
+				if sel {
+					t.win.SetColor(sparta.Foreground, foreColor)
+					t.win.SetColor(sparta.Background, backColor)
+				}
+				if (row == t.row) && (t.rowSel || (col == t.col)) {
+					t.win.Rectangle(image.Rect(r.Min.X, r.Min.Y, r.Max.X-1, r.Max.Y-1), false)
+				}
+				x += w
+			}
+		}
+
+		// column lines
+		x := -hpos
+		for col := 0; (col < cols) && (x < vw); col++ {
+			x += t.width(col)
+			if x > 0 {
+				t.win.Lines([]image.Point{image.Pt(x-1, hh), image.Pt(x-1, vh-1)})
+			}
+		}
+	}
+	rect := image.Rect(0, 0, t.geometry.Dx()-1, t.geometry.Dy()-1)
+	t.win.Rectangle(rect, false)
+}
+
+// HeaderExpose draws the header of the table.
+func (t *Table) headerExpose(w sparta.Widget, e interface{}) bool {
+	win := t.header.Window()
+	win.SetColor(sparta.Foreground, foreColor)
+	hh := t.headerHeight()
+	vw := t.geometry.Dx() - tableScroll
+	if t.table != nil {
+		_, x := t.offset()
+		x = -x
+		for col, cols := 0, t.cols(); (col < cols) && (x < vw); col++ {
+			w := t.width(col)
+			if x+w > 0 {
+				win.Text(image.Pt(x+2, 2), cellText(t.table.Title(col), w))
+				win.Rectangle(image.Rect(x, 0, x+w-1, hh-1), false)
+			}
+			x += w
+		}
+	}
+	win.Lines([]image.Point{image.Pt(0, hh-1), image.Pt(vw-1, hh-1)})
+	return true
+}
+
+// HeaderMouse process the mouse events of the header of the table: the
+// columns are resized dragging its right border, and a click in a title
+// sends the column to the target of the table.
+func (t *Table) headerMouse(w sparta.Widget, e interface{}) bool {
+	if t.table == nil {
+		return true
+	}
+	ev := e.(sparta.MouseEvent)
+	_, hpos := t.offset()
+	switch {
+	case ev.Button == sparta.MouseLeft:
+		col := t.colAt(ev.Loc.X)
+		x := ev.Loc.X + hpos
+		if (col > 0) && (x-t.colPos(col) < 3) {
+			// the right border of the previous column
+			t.resize = col - 1
+			return true
+		}
+		if (col >= 0) && (t.colPos(col)+t.width(col)-x <= 3) {
+			t.resize = col
+			return true
+		}
+		if col >= 0 {
+			sparta.SendEvent(t.target, sparta.CommandEvent{Source: t.header, Value: col})
+		}
+	case (ev.Button == 0) && (t.resize >= 0):
+		if (ev.State & sparta.StateButtonL) == 0 {
+			t.resize = -1
+			break
+		}
+		t.SetColWidth(t.resize, ev.Loc.X+hpos-t.colPos(t.resize))
+	case ev.Button == -sparta.MouseLeft:
+		t.resize = -1
+	}
+	return true
+}
+
+// CellText returns the text of a cell that fits in a column.
+func cellText(s string, width int) string {
+	n := (width - 4) / sparta.WidthUnit
+	if n <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
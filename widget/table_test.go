@@ -0,0 +1,60 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+// TableData is a table that counts the cells read.
+type tableData struct {
+	rows  int
+	reads int
+}
+
+func (d *tableData) Rows() int            { return d.rows }
+func (d *tableData) Cols() int            { return 4 }
+func (d *tableData) Title(c int) string   { return fmt.Sprintf("Column %d", c) }
+func (d *tableData) IsSel(r, c int) bool  { return false }
+func (d *tableData) Cell(r, c int) string { d.reads++; return fmt.Sprintf("r%d c%d", r, c) }
+
+// TableRow returns a point in a row of the table, below the header.
+func tableRow(x, row int) image.Point {
+	return image.Pt(x, sparta.HeightUnit+4+row*sparta.HeightUnit+3)
+}
+
+func TestTable(t *testing.T) {
+	m := widget.NewMainWindow("tableMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 240, 160))
+	tb := widget.NewTable(m, "table", image.Rect(0, 0, 240, 160))
+	d := &tableData{rows: 100000}
+	tb.SetProperty(widget.TableTable, d)
+	tt := sparttest.New(t, m)
+
+	// only the shown cells are read
+	if d.reads > 200 {
+		t.Errorf("%d cells read", d.reads)
+	}
+
+	tt.Click("table", sparta.MouseLeft, tableRow(70, 2))
+	tt.ExpectCommand("tableMain", "table", 2)
+	if c := tb.Property(widget.TableCol).(int); c != 1 {
+		t.Errorf("column %d, want 1", c)
+	}
+
+	tt.Commands()
+	tt.Type("table", sparta.KeyEnd, sparta.KeyRight, sparta.KeyReturn)
+	tt.ExpectCommand("tableMain", "table", 99999)
+	if c := tb.Property(widget.TableCol).(int); c != 2 {
+		t.Errorf("column %d, want 2", c)
+	}
+	m.Close()
+}
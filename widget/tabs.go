@@ -0,0 +1,471 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/js-arias/sparta"
+)
+
+// Tabs is a notebook container: its children are pages that fill the
+// area below a row of tabs, and only the page of the active tab is shown.
+// The text of each tab is the name of the page, or the caption set with
+// SetCaption.
+//
+// The active page is changed by clicking its tab, or with the Ctrl+Tab
+// (Ctrl+Shift+Tab to go backwards), Ctrl+PageDown and Ctrl+PageUp keys.
+// When the active page changes, the tabs widget sends the index of the
+// new active page to the target widget. If the tabs are closable, each
+// tab has a close box.
+type Tabs struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	childs     []sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	widgetState
+
+	active   int
+	closable bool
+	captions map[sparta.Widget]string
+	target   sparta.Widget
+	pending  bool
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// Tabs particular properties.
+const (
+	// sets the active page (int), as the index of the page.
+	TabsActive sparta.Property = "active"
+
+	// sets a close box in each tab (bool).
+	TabsClosable = "closable"
+)
+
+// tabMargin is the space between the text of a tab and its border.
+const tabMargin = 6
+
+// NewTabs creates a new tabs widget.
+func NewTabs(parent sparta.Widget, name string, rect image.Rectangle) *Tabs {
+	t := &Tabs{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		captions: make(map[sparta.Widget]string),
+		target:   parent,
+	}
+	sparta.NewWindow(t)
+	return t
+}
+
+// SetWindow is used by the backend to sets the backend window of the
+// tabs.
+func (t *Tabs) SetWindow(win sparta.Window) {
+	t.win = win
+}
+
+// Window returns the backend window.
+func (t *Tabs) Window() sparta.Window {
+	return t.win
+}
+
+// RemoveWindow removes the backend window.
+func (t *Tabs) RemoveWindow() {
+	t.win = nil
+}
+
+// Property returns the indicated property of the tabs.
+func (t *Tabs) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Childs:
+		return t.childs
+	case sparta.Data:
+		return t.data
+	case sparta.Geometry:
+		return t.geometry
+	case sparta.Parent:
+		return t.parent
+	case sparta.Name:
+		return t.name
+	case sparta.Foreground:
+		return t.fore
+	case sparta.Background:
+		return t.back
+	case sparta.Target:
+		return t.target
+	case sparta.PrefSize:
+		return t.prefSize()
+	case TabsActive:
+		if len(t.pages()) == 0 {
+			return -1
+		}
+		return t.active
+	case TabsClosable:
+		return t.closable
+	}
+	return t.property(p)
+}
+
+// SetProperty sets a property of the tabs.
+func (t *Tabs) SetProperty(p sparta.Property, v interface{}) {
+	if t.setProperty(t.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Childs:
+		if v == nil {
+			t.childs = nil
+			return
+		}
+		t.childs = append(t.childs, v.(sparta.Widget))
+		t.relayout()
+	case sparta.Data:
+		t.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !t.geometry.Eq(val) {
+			t.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			t.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if t.name != val {
+			t.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if t.fore != val {
+			t.fore = val
+			t.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if t.back != val {
+			t.back = val
+			t.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = t.parent
+		}
+		if t.target == val {
+			break
+		}
+		t.target = val
+	case TabsActive:
+		val := v.(int)
+		if n := len(t.pages()); val >= n {
+			val = n - 1
+		}
+		if val < 0 {
+			val = 0
+		}
+		if t.active == val {
+			break
+		}
+		t.active = val
+		sparta.SendEvent(t.target, sparta.CommandEvent{Source: t, Value: t.active})
+		t.relayout()
+	case TabsClosable:
+		val := v.(bool)
+		if t.closable != val {
+			t.closable = val
+			t.Update()
+		}
+	}
+}
+
+// Capture sets an event function of the tabs.
+func (t *Tabs) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		t.closeFn = fn
+	case sparta.Configure:
+		t.configFn = fn
+	case sparta.Command:
+		t.commFn = fn
+	case sparta.Expose:
+		t.exposeFn = fn
+	case sparta.KeyEv:
+		t.keyFn = fn
+	case sparta.Mouse:
+		t.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the tabs.
+func (t *Tabs) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if t.closeFn != nil {
+			t.closeFn(t, e)
+		}
+		for _, ch := range t.childs {
+			ch.OnEvent(e)
+		}
+	case sparta.ConfigureEvent:
+		t.geometry = e.(sparta.ConfigureEvent).Rect
+		if t.configFn != nil {
+			t.configFn(t, e)
+		}
+		t.Layout()
+	case sparta.CommandEvent:
+		ev := e.(sparta.CommandEvent)
+		if ev.Source == t {
+			if t.pending {
+				t.Layout()
+			}
+			return
+		}
+		if t.commFn != nil {
+			if t.commFn(t, e) {
+				return
+			}
+		}
+		t.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if t.exposeFn != nil {
+			t.exposeFn(t, e)
+		}
+		t.draw()
+	case sparta.KeyEvent:
+		if t.keyFn != nil {
+			if t.keyFn(t, e) {
+				return
+			}
+		}
+		ev := e.(sparta.KeyEvent)
+		if n := len(t.pages()); (n > 0) && ((ev.State & sparta.StateCtrl) != 0) {
+			switch ev.Key {
+			case sparta.KeyTab:
+				if (ev.State & sparta.StateShift) != 0 {
+					t.SetProperty(TabsActive, (t.active+n-1)%n)
+				} else {
+					t.SetProperty(TabsActive, (t.active+1)%n)
+				}
+				return
+			case sparta.KeyPageDown:
+				t.SetProperty(TabsActive, (t.active+1)%n)
+				return
+			case sparta.KeyPageUp:
+				t.SetProperty(TabsActive, (t.active+n-1)%n)
+				return
+			}
+		}
+		t.parent.OnEvent(e)
+	case sparta.MouseEvent:
+		if t.mouseFn != nil {
+			if t.mouseFn(t, e) {
+				return
+			}
+		}
+		ev := e.(sparta.MouseEvent)
+		if (ev.Button == sparta.MouseLeft) && (ev.Loc.Y < t.tabHeight()) {
+			pages := t.pages()
+			for i, r := range t.tabRects() {
+				if !ev.Loc.In(r) {
+					continue
+				}
+				if t.closable && ev.Loc.In(t.closeBox(r)) {
+					t.ClosePage(pages[i])
+					return
+				}
+				t.SetProperty(TabsActive, i)
+				return
+			}
+		}
+		ev.Loc = ev.Loc.Add(t.geometry.Min)
+		t.parent.OnEvent(ev)
+	}
+}
+
+// Update updates the tabs.
+func (t *Tabs) Update() {
+	t.win.Update()
+}
+
+// Focus set the focus on the tabs.
+func (t *Tabs) Focus() {
+	t.win.Focus()
+}
+
+// SetCaption sets the text shown in the tab of a page.
+func (t *Tabs) SetCaption(w sparta.Widget, caption string) {
+	t.captions[w] = caption
+	t.Update()
+}
+
+// ClosePage closes a page of the tabs. The page receives a close event
+// before its window is closed. If the closed page was the active page, or
+// a page before it, the tabs widget sends the index of the active page
+// (-1 if there are no more pages) to the target widget.
+func (t *Tabs) ClosePage(w sparta.Widget) {
+	idx := -1
+	for i, p := range t.pages() {
+		if p == w {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	w.OnEvent(sparta.CloseEvent{})
+	w.Window().Close()
+	delete(t.captions, w)
+
+	n := len(t.pages())
+	if idx > t.active {
+		t.relayout()
+		return
+	}
+	if (idx < t.active) || (t.active >= n) {
+		t.active--
+	}
+	if t.active < 0 {
+		t.active = 0
+	}
+	sparta.SendEvent(t.target, sparta.CommandEvent{Source: t, Value: t.Property(TabsActive).(int)})
+	t.relayout()
+}
+
+// Pages returns the pages of the tabs.
+func (t *Tabs) pages() []sparta.Widget {
+	var ws []sparta.Widget
+	for _, c := range t.childs {
+		if c.Window() != nil {
+			ws = append(ws, c)
+		}
+	}
+	return ws
+}
+
+// Caption returns the text of the tab of a page.
+func (t *Tabs) caption(w sparta.Widget) string {
+	if c, ok := t.captions[w]; ok {
+		return c
+	}
+	return w.Property(sparta.Name).(string)
+}
+
+// TabHeight returns the height of the row of tabs.
+func (t *Tabs) tabHeight() int {
+	return sparta.HeightUnit + tabMargin
+}
+
+// TabRects returns the rectangles of the tabs.
+func (t *Tabs) tabRects() []image.Rectangle {
+	h := t.tabHeight()
+	var rs []image.Rectangle
+	x := 0
+	for _, p := range t.pages() {
+		w := len(t.caption(p))*sparta.WidthUnit + 2*tabMargin
+		if t.closable {
+			w += sparta.WidthUnit + tabMargin
+		}
+		rs = append(rs, image.Rect(x, 0, x+w, h))
+		x += w
+	}
+	return rs
+}
+
+// CloseBox returns the close box of a tab.
+func (t *Tabs) closeBox(r image.Rectangle) image.Rectangle {
+	x := r.Max.X - tabMargin - sparta.WidthUnit
+	return image.Rect(x, r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+// Draw draws the row of tabs.
+func (t *Tabs) draw() {
+	t.win.SetColor(sparta.Foreground, foreColor)
+	h := t.tabHeight()
+	pages := t.pages()
+	for i, r := range t.tabRects() {
+		top := r.Min.Y + 2
+		if i == t.active {
+			top = r.Min.Y
+		}
+		y := top + (h-top-sparta.HeightUnit)/2
+		t.win.Text(image.Pt(r.Min.X+tabMargin, y), t.caption(pages[i]))
+		if t.closable {
+			b := t.closeBox(r)
+			t.win.Text(image.Pt(b.Min.X, y), "x")
+		}
+		t.win.Lines([]image.Point{
+			image.Pt(r.Min.X, h-1),
+			image.Pt(r.Min.X, top),
+			image.Pt(r.Max.X-1, top),
+			image.Pt(r.Max.X-1, h-1),
+		})
+		if i != t.active {
+			t.win.Lines([]image.Point{image.Pt(r.Min.X, h-1), image.Pt(r.Max.X-1, h-1)})
+		}
+	}
+	x := 0
+	if rs := t.tabRects(); len(rs) > 0 {
+		x = rs[len(rs)-1].Max.X
+	}
+	if x < t.geometry.Dx() {
+		t.win.Lines([]image.Point{image.Pt(x, h-1), image.Pt(t.geometry.Dx()-1, h-1)})
+	}
+}
+
+// Relayout lays out the pages of the tabs, after all the pending events
+// are processed.
+func (t *Tabs) relayout() {
+	if t.pending || (t.win == nil) {
+		return
+	}
+	t.pending = true
+	sparta.SendEvent(t, sparta.CommandEvent{Source: t})
+}
+
+// Layout sets the geometry of the pages of the tabs, and shows the active
+// page.
+func (t *Tabs) Layout() {
+	t.pending = false
+	r := image.Rect(0, t.tabHeight(), t.geometry.Dx(), t.geometry.Dy())
+	for i, p := range t.pages() {
+		p.SetProperty(sparta.Geometry, r)
+		p.SetProperty(sparta.Visible, i == t.active)
+	}
+	t.win.Update()
+}
+
+// PrefSize returns the preferred size of the tabs, from the preferred size
+// of its pages.
+func (t *Tabs) prefSize() image.Point {
+	var size image.Point
+	for _, p := range t.pages() {
+		pref := prefSize(p)
+		if pref.X > size.X {
+			size.X = pref.X
+		}
+		if pref.Y > size.Y {
+			size.Y = pref.Y
+		}
+	}
+	if rs := t.tabRects(); (len(rs) > 0) && (rs[len(rs)-1].Max.X > size.X) {
+		size.X = rs[len(rs)-1].Max.X
+	}
+	size.Y += t.tabHeight()
+	return size
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func TestTabs(t *testing.T) {
+	m := widget.NewMainWindow("tabsMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 300, 200))
+	tb := widget.NewTabs(m, "tabs", image.Rect(0, 0, 300, 200))
+	l := widget.NewList(tb, "tabsList", image.Rect(0, 0, 1, 1))
+	c := widget.NewCanvas(tb, "tabsCanvas", image.Rect(0, 0, 1, 1))
+	widget.NewEntry(tb, "tabsEntry", image.Rect(0, 0, 1, 1))
+	tb.SetCaption(c, "Drawing")
+	tt := sparttest.New(t, m)
+	expectGeometry(t, l, image.Rect(0, 21, 300, 200))
+	if v := c.Property(sparta.Visible).(bool); v {
+		t.Errorf("inactive page is visible")
+	}
+
+	tt.Click("tabs", sparta.MouseLeft, image.Pt(70, 10))
+	tt.ExpectCommand("tabsMain", "tabs", 1)
+	if a := tb.Property(widget.TabsActive).(int); a != 1 {
+		t.Errorf("active page %d, want 1", a)
+	}
+	if v := c.Property(sparta.Visible).(bool); !v {
+		t.Errorf("active page is not visible")
+	}
+
+	tt.Key("tabsCanvas", sparta.KeyEvent{Key: sparta.KeyPageDown, State: sparta.StateCtrl})
+	if a := tb.Property(widget.TabsActive).(int); a != 2 {
+		t.Errorf("active page %d, want 2", a)
+	}
+	tt.Key("tabsEntry", sparta.KeyEvent{Key: sparta.KeyTab, State: sparta.StateCtrl})
+	if a := tb.Property(widget.TabsActive).(int); a != 0 {
+		t.Errorf("active page %d, want 0", a)
+	}
+	m.Close()
+}
@@ -0,0 +1,632 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"unicode"
+
+	"github.com/js-arias/sparta"
+)
+
+// textPos is a position in a text.
+type textPos struct {
+	line, col int
+}
+
+// less returns true if the position is before other position.
+func (p textPos) less(o textPos) bool {
+	if p.line != o.line {
+		return p.line < o.line
+	}
+	return p.col < o.col
+}
+
+// TextArea is a widget to edit a multi-line text. The text can be
+// selected with the mouse, or with the arrow keys while the shift key is
+// pressed. Lines longer than the widget are scrolled horizontally.
+//
+// Only the visible lines are drawn, so it can be used with large
+// documents.
+type TextArea struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	widgetState
+
+	text     [][]rune
+	caret    textPos
+	anchor   textPos // start of the selection
+	goal     int     // column used when moving between lines
+	offset   int     // first visible column
+	readOnly bool
+	scroll   *Scroll
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// TextArea particular properties.
+const (
+	// sets the text of the text area (string)
+	TextAreaText sparta.Property = "text"
+
+	// appends a text at the end of the text area (string). When read,
+	// it returns nil.
+	TextAreaAppend = "append"
+
+	// sets the caret at the start of the indicated line (int), and
+	// scrolls the text area to make it visible. When read, it
+	// returns the line of the caret.
+	TextAreaLine = "line"
+
+	// number of lines of the text (int). It is read-only.
+	TextAreaLines = "lines"
+
+	// selected text (string). It is read-only.
+	TextAreaSel = "selection"
+
+	// sets the read-only mode (bool). In read-only mode, the text can
+	// be navigated and selected, but not edited.
+	TextAreaReadOnly = "readonly"
+)
+
+// NewTextArea creates a new text area.
+func NewTextArea(parent sparta.Widget, name string, rect image.Rectangle) *TextArea {
+	t := &TextArea{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		text:     [][]rune{nil},
+	}
+	sparta.NewWindow(t)
+	t.scroll = NewScroll(t, "textArea"+name+"Scroll", 1, 0, Vertical, image.Rect(rect.Dx()-10, 0, rect.Dx(), rect.Dy()))
+	t.scroll.SetProperty(ScrollPage, t.page())
+	return t
+}
+
+// SetWindow is used by the backend to sets the backend window of the text
+// area.
+func (t *TextArea) SetWindow(win sparta.Window) {
+	t.win = win
+}
+
+// Window returns the backend window.
+func (t *TextArea) Window() sparta.Window {
+	return t.win
+}
+
+// RemoveWindow removes the backend window.
+func (t *TextArea) RemoveWindow() {
+	t.win = nil
+}
+
+// Property returns the indicated property of the text area.
+func (t *TextArea) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Childs:
+		return []sparta.Widget{t.scroll}
+	case sparta.Data:
+		return t.data
+	case sparta.Geometry:
+		return t.geometry
+	case sparta.Parent:
+		return t.parent
+	case sparta.Name:
+		return t.name
+	case sparta.Foreground:
+		return t.fore
+	case sparta.Background:
+		return t.back
+	case TextAreaText:
+		return t.String()
+	case TextAreaLine:
+		return t.caret.line
+	case TextAreaLines:
+		return len(t.text)
+	case TextAreaSel:
+		s, e := t.selection()
+		return t.substr(s, e)
+	case TextAreaReadOnly:
+		return t.readOnly
+	case sparta.PrefSize:
+		return image.Pt(40*sparta.WidthUnit+12, 10*sparta.HeightUnit+4)
+	}
+	return t.property(p)
+}
+
+// SetProperty sets a property of the text area.
+func (t *TextArea) SetProperty(p sparta.Property, v interface{}) {
+	if t.setProperty(t.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Childs:
+		if v == nil {
+			t.scroll = nil
+		}
+	case sparta.Data:
+		t.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !t.geometry.Eq(val) {
+			t.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			t.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if t.name != val {
+			t.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if t.fore != val {
+			t.fore = val
+			t.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if t.back != val {
+			t.back = val
+			t.win.SetProperty(sparta.Background, val)
+		}
+	case TextAreaText:
+		t.text = splitLines(v.(string))
+		t.caret = textPos{}
+		t.anchor = t.caret
+		t.goal = 0
+		t.offset = 0
+		t.scroll.SetProperty(ScrollSize, len(t.text))
+		t.scroll.SetProperty(ScrollPos, 0)
+		t.Update()
+	case TextAreaAppend:
+		lns := splitLines(v.(string))
+		last := len(t.text) - 1
+		t.text[last] = append(t.text[last], lns[0]...)
+		t.text = append(t.text, lns[1:]...)
+		t.scroll.SetProperty(ScrollSize, len(t.text))
+		t.Update()
+	case TextAreaLine:
+		t.moveCaret(textPos{line: v.(int)}, false)
+		t.goal = 0
+		t.showCaret()
+		t.Update()
+	case TextAreaReadOnly:
+		t.readOnly = v.(bool)
+	}
+}
+
+// SplitLines splits a string in lines.
+func splitLines(s string) [][]rune {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	lns := strings.Split(s, "\n")
+	text := make([][]rune, len(lns))
+	for i, ln := range lns {
+		text[i] = []rune(ln)
+	}
+	return text
+}
+
+// String returns the text of the text area.
+func (t *TextArea) String() string {
+	return t.substr(textPos{}, textPos{line: len(t.text) - 1, col: len(t.text[len(t.text)-1])})
+}
+
+// Substr returns the text between two positions.
+func (t *TextArea) substr(s, e textPos) string {
+	if s.line == e.line {
+		return string(t.text[s.line][s.col:e.col])
+	}
+	lns := make([]string, 0, e.line-s.line+1)
+	lns = append(lns, string(t.text[s.line][s.col:]))
+	for i := s.line + 1; i < e.line; i++ {
+		lns = append(lns, string(t.text[i]))
+	}
+	lns = append(lns, string(t.text[e.line][:e.col]))
+	return strings.Join(lns, "\n")
+}
+
+// Capture sets an event function of the text area.
+func (t *TextArea) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		t.closeFn = fn
+	case sparta.Configure:
+		t.configFn = fn
+	case sparta.Command:
+		t.commFn = fn
+	case sparta.Expose:
+		t.exposeFn = fn
+	case sparta.KeyEv:
+		t.keyFn = fn
+	case sparta.Mouse:
+		t.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the text area.
+func (t *TextArea) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if t.closeFn != nil {
+			t.closeFn(t, e)
+		}
+		t.scroll.OnEvent(e)
+	case sparta.ConfigureEvent:
+		rect := e.(sparta.ConfigureEvent).Rect
+		t.geometry = rect
+		if t.configFn != nil {
+			t.configFn(t, e)
+		}
+		t.scroll.SetProperty(sparta.Geometry, image.Rect(rect.Dx()-10, 0, rect.Dx(), rect.Dy()))
+		t.scroll.SetProperty(ScrollPage, t.page())
+		t.showCaret()
+	case sparta.CommandEvent:
+		if t.commFn != nil {
+			if t.commFn(t, e) {
+				return
+			}
+		}
+		ev := e.(sparta.CommandEvent)
+		if ev.Source == t.scroll {
+			t.Update()
+			return
+		}
+		t.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if t.exposeFn != nil {
+			t.exposeFn(t, e)
+		}
+		t.expose()
+	case sparta.KeyEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(t) {
+				return
+			}
+		}
+		if t.keyFn != nil {
+			if t.keyFn(t, e) {
+				return
+			}
+		}
+		t.key(e.(sparta.KeyEvent))
+	case sparta.MouseEvent:
+		if sparta.IsBlock() {
+			if !sparta.IsBlocker(t) {
+				return
+			}
+		}
+		if t.mouseFn != nil {
+			if t.mouseFn(t, e) {
+				return
+			}
+		}
+		pos := t.pos()
+		ev := e.(sparta.MouseEvent)
+		switch ev.Button {
+		case -sparta.MouseWheel:
+			t.scroll.SetProperty(ScrollPos, pos+1)
+		case sparta.MouseWheel:
+			t.scroll.SetProperty(ScrollPos, pos-1)
+		case sparta.MouseLeft:
+			t.moveCaret(t.posAt(ev.Loc), (ev.State&sparta.StateShift) != 0)
+			t.goal = t.caret.col
+		case 0:
+			if (ev.State & sparta.StateButtonL) != 0 {
+				t.moveCaret(t.posAt(ev.Loc), true)
+				t.goal = t.caret.col
+			}
+		}
+	}
+}
+
+// Expose draws the text area.
+func (t *TextArea) expose() {
+	t.win.SetColor(sparta.Foreground, foreColor)
+	pos := t.pos()
+	cols := t.cols()
+	s, e := t.selection()
+	for i := 0; i < t.page(); i++ {
+		j := i + pos
+		if j >= len(t.text) {
+			break
+		}
+		ln := t.text[j]
+		y := (i * sparta.HeightUnit) + 2
+
+		// selected columns of the line
+		ss, se := -1, -1
+		if (j >= s.line) && (j <= e.line) && s.less(e) {
+			ss, se = 0, len(ln)+1
+			if j == s.line {
+				ss = s.col
+			}
+			if j == e.line {
+				se = e.col
+			}
+		}
+		if t.offset < len(ln) {
+			end := t.offset + cols
+			if end > len(ln) {
+				end = len(ln)
+			}
+			selText(t.win, image.Pt(2, y), ln[t.offset:end], ss-t.offset, se-t.offset, t.back)
+		}
+		if j == t.caret.line {
+			x := 2 + ((t.caret.col - t.offset) * sparta.WidthUnit)
+			t.win.Lines([]image.Point{image.Pt(x-1, y), image.Pt(x-1, y+sparta.HeightUnit-2)})
+		}
+	}
+	rect := image.Rect(0, 0, t.geometry.Dx()-1, t.geometry.Dy()-1)
+	t.win.Rectangle(rect, false)
+}
+
+// Key process a key press.
+func (t *TextArea) key(ev sparta.KeyEvent) {
+	if ev.Key < 0 {
+		return
+	}
+	shift := (ev.State & sparta.StateShift) != 0
+	ctrl := (ev.State & sparta.StateCtrl) != 0
+	c := t.caret
+	switch ev.Key {
+	case sparta.KeyLeft:
+		if c.col > 0 {
+			c.col--
+		} else if c.line > 0 {
+			c.line--
+			c.col = len(t.text[c.line])
+		}
+		t.moveCaret(c, shift)
+		t.goal = t.caret.col
+	case sparta.KeyRight:
+		if c.col < len(t.text[c.line]) {
+			c.col++
+		} else if c.line < len(t.text)-1 {
+			c.line++
+			c.col = 0
+		}
+		t.moveCaret(c, shift)
+		t.goal = t.caret.col
+	case sparta.KeyUp:
+		t.moveCaret(textPos{line: c.line - 1, col: t.goal}, shift)
+	case sparta.KeyDown:
+		t.moveCaret(textPos{line: c.line + 1, col: t.goal}, shift)
+	case sparta.KeyPageUp:
+		t.moveCaret(textPos{line: c.line - t.page(), col: t.goal}, shift)
+	case sparta.KeyPageDown:
+		t.moveCaret(textPos{line: c.line + t.page(), col: t.goal}, shift)
+	case sparta.KeyHome:
+		if ctrl {
+			c.line = 0
+		}
+		c.col = 0
+		t.moveCaret(c, shift)
+		t.goal = t.caret.col
+	case sparta.KeyEnd:
+		if ctrl {
+			c.line = len(t.text) - 1
+		}
+		c.col = len(t.text[c.line])
+		t.moveCaret(c, shift)
+		t.goal = t.caret.col
+	case sparta.KeyBackSpace:
+		if t.readOnly {
+			break
+		}
+		if !t.deleteSel() {
+			if c.col > 0 {
+				c.col--
+			} else if c.line > 0 {
+				c.line--
+				c.col = len(t.text[c.line])
+			} else {
+				break
+			}
+			t.delete(c, t.caret)
+		}
+		t.changed()
+	case sparta.KeyDelete:
+		if t.readOnly {
+			break
+		}
+		if !t.deleteSel() {
+			if c.col < len(t.text[c.line]) {
+				c.col++
+			} else if c.line < len(t.text)-1 {
+				c.line++
+				c.col = 0
+			} else {
+				break
+			}
+			t.delete(t.caret, c)
+		}
+		t.changed()
+	case sparta.KeyReturn, sparta.KeyPadEnter:
+		if t.readOnly {
+			break
+		}
+		t.deleteSel()
+		c = t.caret
+		ln := t.text[c.line]
+		rest := append([]rune(nil), ln[c.col:]...)
+		t.text[c.line] = ln[:c.col]
+		t.text = append(t.text, nil)
+		copy(t.text[c.line+2:], t.text[c.line+1:])
+		t.text[c.line+1] = rest
+		t.caret = textPos{line: c.line + 1}
+		t.changed()
+	default:
+		if ((ev.Key & sparta.KeyNoChar) != 0) || ctrl || ((ev.State & sparta.StateAlt) != 0) {
+			t.parent.OnEvent(ev)
+			return
+		}
+		r := rune(ev.Key)
+		if !unicode.IsPrint(r) {
+			t.parent.OnEvent(ev)
+			return
+		}
+		if t.readOnly {
+			break
+		}
+		t.deleteSel()
+		c = t.caret
+		ln := append(t.text[c.line], 0)
+		copy(ln[c.col+1:], ln[c.col:])
+		ln[c.col] = r
+		t.text[c.line] = ln
+		t.caret.col++
+		t.changed()
+	}
+}
+
+// Changed updates the text area after the text is modified.
+func (t *TextArea) changed() {
+	t.anchor = t.caret
+	t.goal = t.caret.col
+	t.scroll.SetProperty(ScrollSize, len(t.text))
+	t.showCaret()
+	t.Update()
+}
+
+// Delete deletes the text between two positions.
+func (t *TextArea) delete(s, e textPos) {
+	ln := append(t.text[s.line][:s.col], t.text[e.line][e.col:]...)
+	t.text = append(t.text[:s.line+1], t.text[e.line+1:]...)
+	t.text[s.line] = ln
+	t.caret = s
+}
+
+// Selection returns the start and end of the selection.
+func (t *TextArea) selection() (textPos, textPos) {
+	if t.anchor.less(t.caret) {
+		return t.anchor, t.caret
+	}
+	return t.caret, t.anchor
+}
+
+// DeleteSel deletes the selected text. It returns false if there is no
+// selection.
+func (t *TextArea) deleteSel() bool {
+	s, e := t.selection()
+	if s == e {
+		return false
+	}
+	t.delete(s, e)
+	t.anchor = s
+	return true
+}
+
+// MoveCaret moves the caret to the indicated position. If sel is true,
+// the selection is extended to the new position.
+func (t *TextArea) moveCaret(p textPos, sel bool) {
+	if p.line < 0 {
+		p.line = 0
+	} else if p.line >= len(t.text) {
+		p.line = len(t.text) - 1
+	}
+	if p.col < 0 {
+		p.col = 0
+	} else if p.col > len(t.text[p.line]) {
+		p.col = len(t.text[p.line])
+	}
+	if !sel && (t.anchor != t.caret) {
+		t.anchor = t.caret
+		t.Update()
+	}
+	if t.caret == p {
+		return
+	}
+	t.caret = p
+	if !sel {
+		t.anchor = p
+	}
+	t.showCaret()
+	t.Update()
+}
+
+// PosAt returns the text position at a given point.
+func (t *TextArea) posAt(pt image.Point) textPos {
+	p := textPos{
+		line: t.pos() + ((pt.Y - 2) / sparta.HeightUnit),
+		col:  t.offset + ((pt.X - 2 + (sparta.WidthUnit / 2)) / sparta.WidthUnit),
+	}
+	if p.line >= len(t.text) {
+		p.line = len(t.text) - 1
+	}
+	if p.line < 0 {
+		p.line = 0
+	}
+	if p.col > len(t.text[p.line]) {
+		p.col = len(t.text[p.line])
+	}
+	if p.col < 0 {
+		p.col = 0
+	}
+	return p
+}
+
+// Pos returns the first visible line.
+func (t *TextArea) pos() int {
+	pos := t.scroll.Property(ScrollPos).(int)
+	if pos < 0 {
+		return 0
+	}
+	return pos
+}
+
+// Page returns the number of visible lines.
+func (t *TextArea) page() int {
+	return t.geometry.Dy() / sparta.HeightUnit
+}
+
+// Cols returns the number of visible columns.
+func (t *TextArea) cols() int {
+	n := (t.geometry.Dx() - 14) / sparta.WidthUnit
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// ShowCaret scrolls the text area to make the caret visible.
+func (t *TextArea) showCaret() {
+	n := t.cols()
+	if t.caret.col < t.offset {
+		t.offset = t.caret.col
+	} else if t.caret.col >= t.offset+n {
+		t.offset = t.caret.col - n + 1
+	}
+	pos := t.pos()
+	if t.caret.line < pos {
+		t.scroll.SetProperty(ScrollPos, t.caret.line)
+	} else if page := t.page(); (page > 0) && (t.caret.line >= pos+page) {
+		t.scroll.SetProperty(ScrollPos, t.caret.line-page+1)
+	}
+}
+
+// Update updates the text area.
+func (t *TextArea) Update() {
+	t.win.Update()
+}
+
+// Focus set the focus on the text area.
+func (t *TextArea) Focus() {
+	t.win.Focus()
+}
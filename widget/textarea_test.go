@@ -0,0 +1,102 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+func expectAreaText(t *testing.T, ta *widget.TextArea, want string) {
+	t.Helper()
+	if s := ta.Property(widget.TextAreaText).(string); s != want {
+		t.Errorf("text %q, want %q", s, want)
+	}
+}
+
+func TestTextAreaEdit(t *testing.T) {
+	m := widget.NewMainWindow("editAreaMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 100))
+	ta := widget.NewTextArea(m, "editArea", image.Rect(0, 0, 200, 100))
+	tt := sparttest.New(t, m)
+	tt.TypeString("editArea", "first")
+	tt.Type("editArea", sparta.KeyReturn)
+	tt.TypeString("editArea", "second")
+	expectAreaText(t, ta, "first\nsecond")
+	if n := ta.Property(widget.TextAreaLines).(int); n != 2 {
+		t.Errorf("%d lines, want 2", n)
+	}
+
+	// join the lines
+	tt.Type("editArea", sparta.KeyHome, sparta.KeyBackSpace)
+	expectAreaText(t, ta, "firstsecond")
+
+	// select and replace
+	tt.Type("editArea", sparta.KeyHome)
+	for i := 0; i < 5; i++ {
+		tt.Key("editArea", sparta.KeyEvent{Key: sparta.KeyRight, State: sparta.StateShift})
+	}
+	if s := ta.Property(widget.TextAreaSel).(string); s != "first" {
+		t.Errorf("selection %q, want %q", s, "first")
+	}
+	tt.TypeString("editArea", "1st ")
+	expectAreaText(t, ta, "1st second")
+
+	// the read-only mode does not change the text
+	ta.SetProperty(widget.TextAreaReadOnly, true)
+	tt.TypeString("editArea", "x")
+	tt.Type("editArea", sparta.KeyBackSpace, sparta.KeyReturn)
+	expectAreaText(t, ta, "1st second")
+
+	ta.SetProperty(widget.TextAreaAppend, "\nthird")
+	expectAreaText(t, ta, "1st second\nthird")
+	m.Close()
+}
+
+func TestTextAreaLine(t *testing.T) {
+	m := widget.NewMainWindow("lineAreaMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 100))
+	ta := widget.NewTextArea(m, "lineArea", image.Rect(0, 0, 200, 100))
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	ta.SetProperty(widget.TextAreaText, strings.Join(lines, "\n"))
+	tt := sparttest.New(t, m)
+	scroll := tt.Widget("textArealineAreaScroll")
+	tests := []struct {
+		line, pos int
+	}{
+		{50, 45},
+		{52, 47},
+		{10, 10},
+		{99, 94},
+		{0, 0},
+	}
+	for _, test := range tests {
+		ta.SetProperty(widget.TextAreaLine, test.line)
+		tt.Idle()
+		if l := ta.Property(widget.TextAreaLine).(int); l != test.line {
+			t.Errorf("caret line %d, want %d", l, test.line)
+		}
+		if p := scroll.Property(widget.ScrollPos).(int); p != test.pos {
+			t.Errorf("line %d: position %d, want %d", test.line, p, test.pos)
+		}
+	}
+
+	// the caret is moved after the typed text
+	ta.SetProperty(widget.TextAreaLine, 3)
+	tt.TypeString("lineArea", "> ")
+	if s := strings.Split(ta.Property(widget.TextAreaText).(string), "\n")[3]; s != "> line 3" {
+		t.Errorf("line %q, want %q", s, "> line 3")
+	}
+	m.Close()
+}
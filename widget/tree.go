@@ -0,0 +1,476 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reservec.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/js-arias/sparta"
+)
+
+// TreeData is a tree used by a tree control. The nodes of the tree are
+// arbitrary values that must be comparable (for example, pointers), and
+// the nil node is the root of the tree (that is not shown).
+//
+// The children of a node are only requested when the node is expanded,
+// so they can be loaded lazily.
+type TreeData interface {
+	// Len returns the number of children of a node.
+	Len(node interface{}) int
+
+	// Child returns the i-th child of a node.
+	Child(node interface{}, i int) interface{}
+
+	// Item returns the name of a node.
+	Item(node interface{}) string
+
+	// IsLeaf returns true if a node does not have children. It is used
+	// to show the node markers without loading the children of the
+	// node.
+	IsLeaf(node interface{}) bool
+
+	// IsSel returns true if a node is selected.
+	IsSel(node interface{}) bool
+}
+
+// Tree is a widget that shows a tree of strings, in which each node can
+// be expanded, to show its children, or collapsed. A node is expanded or
+// collapsed by clicking its marker, or with the right and left arrow keys.
+//
+// The nodes are shown as rows, in which the visible nodes are numbered
+// from the top. When a node is selected, it sends a command event to its
+// target widget indicating the row of the selected node (that can be
+// translated to the node with the function Node). It will be a positive
+// number if the selection is made with the left button (or with the
+// return or space keys) or negative (starting at -1), if it was with the
+// right button.
+//
+// It is up to client code to manage multiple or single selection.
+type Tree struct {
+	name       string
+	win        sparta.Window
+	parent     sparta.Widget
+	geometry   image.Rectangle
+	fore, back color.RGBA
+	data       interface{}
+	widgetState
+
+	tree     TreeData
+	expanded map[interface{}]bool
+	rows     []treeRow
+	cur      int // row of the cursor
+	target   sparta.Widget
+	scroll   *Scroll
+
+	closeFn  func(sparta.Widget, interface{}) bool
+	commFn   func(sparta.Widget, interface{}) bool
+	configFn func(sparta.Widget, interface{}) bool
+	exposeFn func(sparta.Widget, interface{}) bool
+	keyFn    func(sparta.Widget, interface{}) bool
+	mouseFn  func(sparta.Widget, interface{}) bool
+}
+
+// treeRow is a visible node of a tree.
+type treeRow struct {
+	node  interface{}
+	depth int
+	leaf  bool
+}
+
+// Tree particular properties.
+const (
+	// sets the tree (TreeData). As the tree is only read when it is
+	// displayed, the property should be set again, when the tree
+	// changes.
+	TreeTree sparta.Property = "tree"
+)
+
+// NewTree creates a new tree.
+func NewTree(parent sparta.Widget, name string, rect image.Rectangle) *Tree {
+	t := &Tree{
+		name:     name,
+		parent:   parent,
+		geometry: rect,
+		back:     backColor,
+		fore:     foreColor,
+		expanded: make(map[interface{}]bool),
+		target:   parent,
+	}
+	sparta.NewWindow(t)
+	t.scroll = NewScroll(t, "tree"+name+"Scroll", 0, 0, Vertical, image.Rect(rect.Dx()-10, 0, rect.Dx(), rect.Dy()))
+	return t
+}
+
+// SetWindow is used by the backend to sets the backend window of the tree.
+func (t *Tree) SetWindow(win sparta.Window) {
+	t.win = win
+}
+
+// Window returns the backend window.
+func (t *Tree) Window() sparta.Window {
+	return t.win
+}
+
+// RemoveWindow removes the backend window.
+func (t *Tree) RemoveWindow() {
+	t.win = nil
+}
+
+// Property returns the indicated property of the tree.
+func (t *Tree) Property(p sparta.Property) interface{} {
+	switch p {
+	case sparta.Childs:
+		return []sparta.Widget{t.scroll}
+	case sparta.Data:
+		return t.data
+	case sparta.Geometry:
+		return t.geometry
+	case sparta.Parent:
+		return t.parent
+	case sparta.Name:
+		return t.name
+	case sparta.Foreground:
+		return t.fore
+	case sparta.Background:
+		return t.back
+	case sparta.Target:
+		return t.target
+	case TreeTree:
+		return t.tree
+	case sparta.PrefSize:
+		return image.Pt(21*sparta.WidthUnit+12, 8*sparta.HeightUnit+4)
+	}
+	return t.property(p)
+}
+
+// SetProperty sets a property of the tree.
+func (t *Tree) SetProperty(p sparta.Property, v interface{}) {
+	if t.setProperty(t.win, p, v) {
+		return
+	}
+	switch p {
+	case sparta.Childs:
+		if v == nil {
+			t.scroll = nil
+		}
+	case sparta.Data:
+		t.data = v
+	case sparta.Geometry:
+		val := v.(image.Rectangle)
+		if !t.geometry.Eq(val) {
+			t.win.SetProperty(sparta.Geometry, val)
+		}
+	case sparta.Parent:
+		if v == nil {
+			t.parent = nil
+		}
+	case sparta.Name:
+		val := v.(string)
+		if t.name != val {
+			t.name = val
+		}
+	case sparta.Foreground:
+		val := v.(color.RGBA)
+		if t.fore != val {
+			t.fore = val
+			t.win.SetProperty(sparta.Foreground, val)
+		}
+	case sparta.Background:
+		val := v.(color.RGBA)
+		if t.back != val {
+			t.back = val
+			t.win.SetProperty(sparta.Background, val)
+		}
+	case sparta.Target:
+		val := v.(sparta.Widget)
+		if val == nil {
+			val = t.parent
+		}
+		if t.target == val {
+			break
+		}
+		t.target = val
+	case TreeTree:
+		if v == nil {
+			t.tree = nil
+		} else {
+			t.tree = v.(TreeData)
+		}
+		t.scroll.SetProperty(ScrollSize, 0)
+		t.build()
+	}
+}
+
+// Capture sets an event function of the tree.
+func (t *Tree) Capture(e sparta.EventType, fn func(sparta.Widget, interface{}) bool) {
+	switch e {
+	case sparta.CloseEv:
+		t.closeFn = fn
+	case sparta.Configure:
+		t.configFn = fn
+	case sparta.Command:
+		t.commFn = fn
+	case sparta.Expose:
+		t.exposeFn = fn
+	case sparta.KeyEv:
+		t.keyFn = fn
+		t.scroll.Capture(e, fn)
+	case sparta.Mouse:
+		t.mouseFn = fn
+	}
+}
+
+// OnEvent process a particular event on the tree.
+func (t *Tree) OnEvent(e interface{}) {
+	switch e.(type) {
+	case sparta.CloseEvent:
+		if t.closeFn != nil {
+			t.closeFn(t, e)
+		}
+		t.scroll.OnEvent(e)
+	case sparta.ConfigureEvent:
+		rect := e.(sparta.ConfigureEvent).Rect
+		t.geometry = rect
+		if t.configFn != nil {
+			t.configFn(t, e)
+		}
+		t.scroll.SetProperty(sparta.Geometry, image.Rect(rect.Dx()-10, 0, rect.Dx(), rect.Dy()))
+		t.scroll.SetProperty(ScrollPage, t.geometry.Dy()/sparta.HeightUnit)
+	case sparta.CommandEvent:
+		if t.commFn != nil {
+			if t.commFn(t, e) {
+				return
+			}
+		}
+		ev := e.(sparta.CommandEvent)
+		if ev.Source == t.scroll {
+			t.Update()
+			return
+		}
+		t.parent.OnEvent(e)
+	case sparta.ExposeEvent:
+		if t.exposeFn != nil {
+			t.exposeFn(t, e)
+		}
+		t.win.SetColor(sparta.Foreground, foreColor)
+		pos := t.scroll.Property(ScrollPos).(int)
+		if pos < 0 {
+			pos = 0
+		}
+		page := t.scroll.Property(ScrollPage).(int)
+		for i := 0; i <= page; i++ {
+			j := i + pos
+			if j >= len(t.rows) {
+				break
+			}
+			r := t.rows[j]
+			y := (i * sparta.HeightUnit) + 2
+			x := t.indent(r.depth)
+			if t.tree.IsSel(r.node) {
+				t.win.Text(image.Pt(2, y), ">")
+			}
+			if !r.leaf {
+				if t.expanded[r.node] {
+					t.win.Text(image.Pt(x, y), "-")
+				} else {
+					t.win.Text(image.Pt(x, y), "+")
+				}
+			}
+			t.win.Text(image.Pt(x+2*sparta.WidthUnit, y), t.tree.Item(r.node))
+			if j == t.cur {
+				rect := image.Rect(x+2*sparta.WidthUnit-3, y-2, t.geometry.Dx()-12, y+sparta.HeightUnit-2)
+				t.win.Rectangle(rect, false)
+			}
+		}
+		rect := image.Rect(0, 0, t.geometry.Dx()-1, t.geometry.Dy()-1)
+		t.win.Rectangle(rect, false)
+	case sparta.KeyEvent:
+		if t.keyFn != nil {
+			if t.keyFn(t, e) {
+				return
+			}
+		}
+		page := t.scroll.Property(ScrollPage).(int)
+		ev := e.(sparta.KeyEvent)
+		switch ev.Key {
+		case sparta.KeyDown:
+			t.setCursor(t.cur + 1)
+		case sparta.KeyUp:
+			t.setCursor(t.cur - 1)
+		case sparta.KeyPageUp:
+			t.setCursor(t.cur - page)
+		case sparta.KeyPageDown:
+			t.setCursor(t.cur + page)
+		case sparta.KeyHome:
+			t.setCursor(0)
+		case sparta.KeyEnd:
+			t.setCursor(len(t.rows) - 1)
+		case sparta.KeyLeft:
+			if t.cur >= len(t.rows) {
+				break
+			}
+			r := t.rows[t.cur]
+			if !r.leaf && t.expanded[r.node] {
+				t.SetExpanded(r.node, false)
+				break
+			}
+			for i := t.cur - 1; i >= 0; i-- {
+				if t.rows[i].depth < r.depth {
+					t.setCursor(i)
+					break
+				}
+			}
+		case sparta.KeyRight:
+			if t.cur >= len(t.rows) {
+				break
+			}
+			r := t.rows[t.cur]
+			if r.leaf {
+				break
+			}
+			if !t.expanded[r.node] {
+				t.SetExpanded(r.node, true)
+				break
+			}
+			t.setCursor(t.cur + 1)
+		case ' ', sparta.KeyReturn, sparta.KeyPadEnter:
+			if t.cur < len(t.rows) {
+				sparta.SendEvent(t.target, sparta.CommandEvent{Source: t, Value: t.cur})
+			}
+		default:
+			t.parent.OnEvent(e)
+		}
+	case sparta.MouseEvent:
+		if t.mouseFn != nil {
+			if t.mouseFn(t, e) {
+				return
+			}
+		}
+		pos := t.scroll.Property(ScrollPos).(int)
+		ev := e.(sparta.MouseEvent)
+		switch ev.Button {
+		case -sparta.MouseWheel:
+			t.scroll.SetProperty(ScrollPos, pos+1)
+		case sparta.MouseWheel:
+			t.scroll.SetProperty(ScrollPos, pos-1)
+		case sparta.MouseLeft, sparta.MouseRight:
+			p := ((ev.Loc.Y - 2) / sparta.HeightUnit) + pos
+			if (p < 0) || (p >= len(t.rows)) {
+				break
+			}
+			r := t.rows[p]
+			x := t.indent(r.depth)
+			if !r.leaf && (ev.Loc.X >= x) && (ev.Loc.X < x+2*sparta.WidthUnit) {
+				t.SetExpanded(r.node, !t.expanded[r.node])
+				break
+			}
+			t.cur = p
+			t.Update()
+			if ev.Button == sparta.MouseRight {
+				p = -(p + 1)
+			}
+			sparta.SendEvent(t.target, sparta.CommandEvent{Source: t, Value: p})
+		}
+	}
+}
+
+// Update updates the tree.
+func (t *Tree) Update() {
+	t.win.Update()
+}
+
+// Focus set the focus on the tree.
+func (t *Tree) Focus() {
+	t.win.Focus()
+}
+
+// Node returns the node shown at a given row. It returns nil if there is
+// no row.
+func (t *Tree) Node(row int) interface{} {
+	if (row < 0) || (row >= len(t.rows)) {
+		return nil
+	}
+	return t.rows[row].node
+}
+
+// SetExpanded expands or collapses a node of the tree. The expansion state
+// of the descendants of a collapsed node is kept.
+func (t *Tree) SetExpanded(node interface{}, expand bool) {
+	if t.expanded[node] == expand {
+		return
+	}
+	if expand {
+		t.expanded[node] = true
+	} else {
+		delete(t.expanded, node)
+	}
+	t.build()
+}
+
+// IsExpanded returns true if a node of the tree is expanded.
+func (t *Tree) IsExpanded(node interface{}) bool {
+	return t.expanded[node]
+}
+
+// Build sets the rows of the visible nodes of the tree.
+func (t *Tree) build() {
+	var node interface{}
+	if t.cur < len(t.rows) {
+		node = t.rows[t.cur].node
+	}
+	t.rows = t.rows[:0]
+	if t.tree != nil {
+		t.addRows(nil, 0)
+	}
+
+	// keeps the cursor in the same node
+	t.cur = 0
+	for i, r := range t.rows {
+		if r.node == node {
+			t.cur = i
+			break
+		}
+	}
+	t.scroll.SetProperty(ScrollSize, len(t.rows))
+	t.scroll.SetProperty(ScrollPage, t.geometry.Dy()/sparta.HeightUnit)
+	t.Update()
+}
+
+// AddRows adds the rows of the children of an expanded node.
+func (t *Tree) addRows(node interface{}, depth int) {
+	for i, n := 0, t.tree.Len(node); i < n; i++ {
+		c := t.tree.Child(node, i)
+		leaf := t.tree.IsLeaf(c)
+		t.rows = append(t.rows, treeRow{node: c, depth: depth, leaf: leaf})
+		if !leaf && t.expanded[c] {
+			t.addRows(c, depth+1)
+		}
+	}
+}
+
+// SetCursor moves the cursor to a row, and scrolls the tree to show it.
+func (t *Tree) setCursor(row int) {
+	if row >= len(t.rows) {
+		row = len(t.rows) - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+	t.cur = row
+	pos := t.scroll.Property(ScrollPos).(int)
+	page := t.scroll.Property(ScrollPage).(int)
+	if row < pos {
+		t.scroll.SetProperty(ScrollPos, row)
+	} else if (page > 0) && (row >= pos+page) {
+		t.scroll.SetProperty(ScrollPos, row-page+1)
+	}
+	t.Update()
+}
+
+// Indent returns the position of the marker of a node at a given depth.
+func (t *Tree) indent(depth int) int {
+	return 2 + sparta.WidthUnit + depth*2*sparta.WidthUnit
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2014, J. Salvador Arias <jsalarias@gmail.com>
+// All rights reserved.
+// Distributed under BSD2 license that can be found in LICENSE file.
+
+package widget_test
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/js-arias/sparta"
+	"github.com/js-arias/sparta/sparttest"
+	"github.com/js-arias/sparta/widget"
+)
+
+// TreeNode is a node of a test tree.
+type treeNode struct {
+	name   string
+	depth  int
+	kids   []*treeNode
+	loaded bool
+}
+
+// TreeData is a tree in which each node, up to a depth of 3, has three
+// children, that are loaded when requested.
+type treeData struct {
+	root  *treeNode
+	loads int
+}
+
+func (d *treeData) node(n interface{}) *treeNode {
+	if n == nil {
+		return d.root
+	}
+	return n.(*treeNode)
+}
+
+func (d *treeData) Len(n interface{}) int {
+	nd := d.node(n)
+	if !nd.loaded {
+		nd.loaded = true
+		d.loads++
+		for i := 0; i < 3; i++ {
+			nd.kids = append(nd.kids, &treeNode{name: fmt.Sprintf("%s.%d", nd.name, i), depth: nd.depth + 1})
+		}
+	}
+	return len(nd.kids)
+}
+
+func (d *treeData) Child(n interface{}, i int) interface{} { return d.node(n).kids[i] }
+func (d *treeData) Item(n interface{}) string              { return n.(*treeNode).name }
+func (d *treeData) IsLeaf(n interface{}) bool              { return n.(*treeNode).depth >= 3 }
+func (d *treeData) IsSel(n interface{}) bool               { return false }
+
+func TestTree(t *testing.T) {
+	m := widget.NewMainWindow("treeMain", "test")
+	m.SetProperty(sparta.Geometry, image.Rect(0, 0, 200, 150))
+	tr := widget.NewTree(m, "tree", image.Rect(0, 0, 200, 150))
+	d := &treeData{root: &treeNode{name: "n"}}
+	tr.SetProperty(widget.TreeTree, d)
+	tt := sparttest.New(t, m)
+	if d.loads != 1 {
+		t.Errorf("%d nodes loaded, want 1", d.loads)
+	}
+
+	// expand the first node, and select its first child
+	tt.Type("tree", sparta.KeyRight, sparta.KeyRight, sparta.KeyReturn)
+	tt.ExpectCommand("treeMain", "tree", 1)
+	if d.loads != 2 {
+		t.Errorf("%d nodes loaded, want 2", d.loads)
+	}
+	if n := tr.Node(1).(*treeNode); n.name != "n.0.0" {
+		t.Errorf("node %q, want %q", n.name, "n.0.0")
+	}
+
+	// collapse the first node
+	tt.Type("tree", sparta.KeyLeft, sparta.KeyLeft)
+	if tr.IsExpanded(tr.Node(0)) {
+		t.Errorf("node %q not collapsed", tr.Node(0).(*treeNode).name)
+	}
+	if n := tr.Node(1).(*treeNode); n.name != "n.1" {
+		t.Errorf("node %q, want %q", n.name, "n.1")
+	}
+
+	// expand with a click on the marker
+	tt.Click("tree", sparta.MouseLeft, image.Pt(2+sparta.WidthUnit, 2+sparta.HeightUnit+3))
+	if !tr.IsExpanded(tr.Node(1)) {
+		t.Errorf("node %q not expanded", tr.Node(1).(*treeNode).name)
+	}
+	m.Close()
+}
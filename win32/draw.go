@@ -49,11 +49,8 @@ func (win *window) Draw(mode bool) {
 		win.dc = w32.GetDC(win.id)
 		w32.SetBkMode(win.dc, w32.TRANSPARENT)
 		w32.SetBkColor(win.dc, win.back.color)
-		w32.SelectObject(win.dc, w32.HGDIOBJ(win.fore.brush))
-		w32.SelectObject(win.dc, w32.HGDIOBJ(win.fore.pen))
 		w32.SelectObject(win.dc, w32.HGDIOBJ(winFont))
-		w32.SetTextColor(win.dc, win.fore.color)
-		win.curr = win.fore
+		win.resetFore()
 		return
 	}
 	if win.dc == 0 {
@@ -63,6 +60,19 @@ func (win *window) Draw(mode bool) {
 	win.dc = 0
 }
 
+// ResetFore sets the fore color of the window as the drawing color. The
+// color is grayed if the window is disabled.
+func (win *window) resetFore() {
+	if !isEnabled(win.w) {
+		win.SetColor(sparta.Foreground, win.w.Property(sparta.Foreground).(color.RGBA))
+		return
+	}
+	w32.SelectObject(win.dc, w32.HGDIOBJ(win.fore.brush))
+	w32.SelectObject(win.dc, w32.HGDIOBJ(win.fore.pen))
+	w32.SetTextColor(win.dc, win.fore.color)
+	win.curr = win.fore
+}
+
 // Text draws text in the window.
 func (win *window) Text(pt image.Point, text string) {
 	if len(text) == 0 {
@@ -161,6 +171,9 @@ func (win *window) SetColor(p sparta.Property, c color.RGBA) {
 	if win.dc == 0 {
 		return
 	}
+	if (p == sparta.Foreground) && !isEnabled(win.w) {
+		c = grayed(c)
+	}
 	b := getBrush(c)
 	if p == sparta.Foreground {
 		w32.SelectObject(win.dc, w32.HGDIOBJ(b.brush))
@@ -171,3 +184,14 @@ func (win *window) SetColor(p sparta.Property, c color.RGBA) {
 		w32.SetBkColor(win.dc, b.color)
 	}
 }
+
+// Grayed returns the grayed version of a color, used to draw the
+// foreground of disabled windows.
+func grayed(c color.RGBA) color.RGBA {
+	return color.RGBA{
+		R: c.R/3 + 128,
+		G: c.G/3 + 128,
+		B: c.B/3 + 128,
+		A: c.A,
+	}
+}
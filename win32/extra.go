@@ -132,9 +132,30 @@ func setPixel(hdc w32.HDC, x, y int, color w32.COLORREF) w32.COLORREF {
 
 var moduser32 = syscall.NewLazyDLL("user32.dll")
 
-var procGetKeyState = moduser32.NewProc("GetKeyState")
+var (
+	procGetKeyState     = moduser32.NewProc("GetKeyState")
+	procClientToScreen  = moduser32.NewProc("ClientToScreen")
+	procWindowFromPoint = moduser32.NewProc("WindowFromPoint")
+)
 
 func GetKeyState(nVirtKey int) int16 {
 	ret, _, _ := procGetKeyState.Call(uintptr(nVirtKey))
 	return int16(ret)
 }
+
+func clientToScreen(hwnd w32.HWND, x, y int) (int, int) {
+	pt := w32.POINT{X: int32(x), Y: int32(y)}
+	procClientToScreen.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&pt)))
+	return int(pt.X), int(pt.Y)
+}
+
+func windowFromPoint(x, y int) w32.HWND {
+	var ret uintptr
+	// the POINT structure is passed by value
+	if unsafe.Sizeof(ret) == 8 {
+		ret, _, _ = procWindowFromPoint.Call(uintptr(uint32(x)) | (uintptr(uint32(y)) << 32))
+	} else {
+		ret, _, _ = procWindowFromPoint.Call(uintptr(x), uintptr(y))
+	}
+	return w32.HWND(ret)
+}
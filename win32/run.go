@@ -20,6 +20,8 @@ func init() {
 	sparta.Run = run
 	sparta.Close = closeApp
 	sparta.SendEvent = sendEvent
+	sparta.Grab = grabPointer
+	sparta.Ungrab = ungrab
 }
 
 // SendEvent sends an event to a widget.
@@ -57,6 +59,67 @@ func closeApp() {
 	w32.PostQuitMessage(0)
 }
 
+// grab is the widget that grabs the pointer.
+var grab sparta.Widget
+
+// GrabPointer grabs the pointer for a widget.
+func grabPointer(w sparta.Widget) {
+	win := w.Window().(*window)
+	grab = w
+	w32.SetCapture(win.id)
+}
+
+// Ungrab releases the pointer.
+func ungrab() {
+	if grab == nil {
+		return
+	}
+	grab = nil
+	w32.ReleaseCapture()
+}
+
+// InGrab returns true if the widget is the grabbing widget, or one of its
+// descendants.
+func inGrab(w sparta.Widget) bool {
+	for w != nil {
+		if w == grab {
+			return true
+		}
+		p := w.Property(sparta.Parent)
+		if p == nil {
+			break
+		}
+		w = p.(sparta.Widget)
+	}
+	return false
+}
+
+// Grabbed returns the widget that receives a pointer event produced in
+// a window, and the location of the event, relative to that widget. As
+// the captured events are all sent to the grabbing window, the event is
+// sent to the window under the pointer, if it is a descendant of the
+// grabbing widget.
+func grabbed(w sparta.Widget, id w32.HWND, pt image.Point) (sparta.Widget, image.Point) {
+	if grab == nil {
+		return w, pt
+	}
+	x, y := clientToScreen(id, pt.X, pt.Y)
+	hwnd := windowFromPoint(x, y)
+	if tw, ok := widgetTable[hwnd]; ok && inGrab(tw) {
+		pt.X, pt.Y, _ = w32.ScreenToClient(hwnd, x, y)
+		return tw, pt
+	}
+	gWin := grab.Window().(*window)
+	pt.X, pt.Y, _ = w32.ScreenToClient(gWin.id, x, y)
+	return grab, pt
+}
+
+// IsBlocked returns true if the input of a widget is blocked by other
+// widget (for example, a modal dialog).
+func isBlocked(w sparta.Widget) bool {
+	return sparta.IsBlock() && !sparta.IsBlocker(w)
+}
+
 // WinEvent proccess a win32 event.
 func winEvent(id w32.HWND, event uint32, wParam, lParam uintptr) uintptr {
 	w, ok := widgetTable[id]
@@ -80,11 +143,46 @@ func winEvent(id w32.HWND, event uint32, wParam, lParam uintptr) uintptr {
 			Key:   sparta.Key(key),
 			State: getState(),
 		}
+		if isBlocked(w) {
+			break
+		}
+		x, y, _ := w32.GetCursorPos()
+		ev.Loc.X, ev.Loc.Y, _ = w32.ScreenToClient(id, x, y)
+		w.OnEvent(ev)
+	case w32.WM_SYSCHAR:
+		// characters typed with the alt key (e.g. menu mnemonics), alt
+		// and space is left to the system menu.
+		r := utf16.Decode([]uint16{uint16(loWord(uint32(wParam)))})
+		if (len(r) == 0) || (r[0] == 0) || (r[0] == ' ') {
+			return w32.DefWindowProc(id, event, wParam, lParam)
+		}
+		ev := sparta.KeyEvent{
+			Key:   sparta.Key(r[0]),
+			State: getState(),
+		}
+		if isBlocked(w) {
+			break
+		}
+		x, y, _ := w32.GetCursorPos()
+		ev.Loc.X, ev.Loc.Y, _ = w32.ScreenToClient(id, x, y)
+		w.OnEvent(ev)
+	case w32.WM_SYSKEYDOWN:
+		// F10 is a system key, used to open the menu
+		if wParam != w32.VK_F10 {
+			return w32.DefWindowProc(id, event, wParam, lParam)
+		}
+		ev := sparta.KeyEvent{
+			Key:   sparta.KeyF10,
+			State: getState(),
+		}
+		if isBlocked(w) {
+			break
+		}
 		x, y, _ := w32.GetCursorPos()
 		ev.Loc.X, ev.Loc.Y, _ = w32.ScreenToClient(id, x, y)
 		w.OnEvent(ev)
 	case w32.WM_CLOSE:
-		if w.Property(sparta.Parent) != nil {
+		if (w.Property(sparta.Parent) != nil) || isBlocked(w) {
 			break
 		}
 		w.OnEvent(sparta.CloseEvent{})
@@ -100,6 +198,9 @@ func winEvent(id w32.HWND, event uint32, wParam, lParam uintptr) uintptr {
 			Key:   key,
 			State: getState(),
 		}
+		if isBlocked(w) {
+			break
+		}
 		x, y, _ := w32.GetCursorPos()
 		ev.Loc.X, ev.Loc.Y, _ = w32.ScreenToClient(id, x, y)
 		w.OnEvent(ev)
@@ -112,6 +213,9 @@ func winEvent(id w32.HWND, event uint32, wParam, lParam uintptr) uintptr {
 			Key:   -key,
 			State: getState(),
 		}
+		if isBlocked(w) {
+			break
+		}
 		x, y, _ := w32.GetCursorPos()
 		ev.Loc.X, ev.Loc.Y, _ = w32.ScreenToClient(id, x, y)
 		w.OnEvent(ev)
@@ -119,20 +223,30 @@ func winEvent(id w32.HWND, event uint32, wParam, lParam uintptr) uintptr {
 		ev := sparta.MouseEvent{
 			Button: getButton(event),
 			State:  getState(),
-			Loc:    image.Pt(getXLParam(lParam), getYLParam(lParam)),
 		}
-		w.OnEvent(ev)
+		w, ev.Loc = grabbed(w, id, image.Pt(getXLParam(lParam), getYLParam(lParam)))
+		if isBlocked(w) {
+			break
+		}
 		w.Focus()
+		w.OnEvent(ev)
 	case w32.WM_LBUTTONUP, w32.WM_RBUTTONUP, w32.WM_MBUTTONUP:
 		ev := sparta.MouseEvent{
 			Button: -getButton(event),
 			State:  getState(),
-			Loc:    image.Pt(getXLParam(lParam), getYLParam(lParam)),
+		}
+		w, ev.Loc = grabbed(w, id, image.Pt(getXLParam(lParam), getYLParam(lParam)))
+		if isBlocked(w) {
+			break
 		}
 		w.OnEvent(ev)
 	case w32.WM_MOUSEMOVE:
 		ev := sparta.MouseEvent{
-			Loc: image.Pt(getXLParam(lParam), getYLParam(lParam)),
+			State: getState(),
+		}
+		w, ev.Loc = grabbed(w, id, image.Pt(getXLParam(lParam), getYLParam(lParam)))
+		if isBlocked(w) {
+			break
 		}
 		w.OnEvent(ev)
 	case w32.WM_MOUSEWHEEL:
@@ -144,10 +258,17 @@ func winEvent(id w32.HWND, event uint32, wParam, lParam uintptr) uintptr {
 		}
 		ev.Loc.X, ev.Loc.Y, _ = w32.ScreenToClient(id, getXLParam(lParam), getYLParam(lParam))
 		w = propagateWheel(w, ev.Loc)
+		if isBlocked(w) {
+			break
+		}
 		w.OnEvent(ev)
 	case w32.WM_MOVE:
 		win := w.Window().(*window)
 		win.pos.X, win.pos.Y = int(loWord(uint32(lParam))), int(hiWord(uint32(lParam)))
+		rect := w.Property(sparta.Geometry).(image.Rectangle)
+		if !rect.Min.Eq(win.pos) {
+			w.OnEvent(sparta.ConfigureEvent{Rect: rect.Sub(rect.Min).Add(win.pos)})
+		}
 	case w32.WM_PAINT:
 		win := w.Window().(*window)
 		ps := &w32.PAINTSTRUCT{}
@@ -162,11 +283,8 @@ func winEvent(id w32.HWND, event uint32, wParam, lParam uintptr) uintptr {
 		w32.SelectObject(win.dc, w32.HGDIOBJ(win.back.pen))
 		w32.Rectangle(win.dc, int(ps.RcPaint.Left), int(ps.RcPaint.Top), int(ps.RcPaint.Right), int(ps.RcPaint.Bottom))
 
-		w32.SelectObject(win.dc, w32.HGDIOBJ(win.fore.brush))
-		w32.SelectObject(win.dc, w32.HGDIOBJ(win.fore.pen))
 		w32.SelectObject(win.dc, w32.HGDIOBJ(winFont))
-		w32.SetTextColor(win.dc, win.fore.color)
-		win.curr = win.fore
+		win.resetFore()
 
 		ev := sparta.ExposeEvent{image.Rect(int(ps.RcPaint.Left), int(ps.RcPaint.Top), int(ps.RcPaint.Right), int(ps.RcPaint.Bottom))}
 		w.OnEvent(ev)
@@ -231,6 +349,9 @@ func getState() sparta.StateKey {
 	if GetKeyState(w32.VK_CONTROL) < 0 {
 		state |= sparta.StateCtrl
 	}
+	if GetKeyState(w32.VK_MENU) < 0 {
+		state |= sparta.StateAlt
+	}
 	if GetKeyState(w32.VK_LBUTTON) < 0 {
 		state |= sparta.StateButtonL
 	}
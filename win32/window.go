@@ -19,6 +19,9 @@ import (
 // WidgetTable holds a list of widgets.
 var widgetTable = make(map[w32.HWND]sparta.Widget)
 
+// focus is the window with the input focus.
+var focus *window
+
 var (
 	// Window extra borders
 	extraX = (2 * w32.GetSystemMetrics(w32.SM_CXFRAME)) + 4
@@ -36,17 +39,40 @@ type window struct {
 	isPaint    bool    // true if the window is processing a PAINT event.
 	back, fore *brush
 	curr       *brush
+	disabled   bool
 }
 
 func init() {
 	sparta.NewWindow = newWindow
+	sparta.RootPos = rootPos
+	sparta.Focused = focused
 }
 
 // NewWindow creates a new window and assigns it to a widget.
 func newWindow(w sparta.Widget) {
 	var win *window
 	rect := w.Property(sparta.Geometry).(image.Rectangle)
-	if p := w.Property(sparta.Parent); p != nil {
+	popup := false
+	if v, ok := w.Property(sparta.Popup).(bool); ok {
+		popup = v
+	}
+	if popup {
+		win = &window{
+			w:    w,
+			back: bkGround,
+			fore: frGround,
+			pos:  rect.Min,
+		}
+		win.id = w32.CreateWindowEx(uint(w32.WS_EX_TOOLWINDOW|w32.WS_EX_TOPMOST),
+			stringToUTF16(baseClass), stringToUTF16(""),
+			uint(w32.WS_POPUP),
+			rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy(),
+			0, 0, instance, nil)
+		if win.id == 0 {
+			log.Printf("w32: error: %v\n", getLastError())
+			os.Exit(1)
+		}
+	} else if p := w.Property(sparta.Parent); p != nil {
 		pW := p.(sparta.Widget)
 		pWin := pW.Window().(*window)
 		win = &window{
@@ -72,11 +98,20 @@ func newWindow(w sparta.Widget) {
 			back: bkGround,
 			fore: frGround,
 		}
+		x, y := 150, 150
+		var owner w32.HWND
+		if o, ok := w.Property(sparta.Owner).(sparta.Widget); ok && (o != nil) {
+			if oWin, ok := o.Window().(*window); ok {
+				// an owned window is always over its owner
+				owner = oWin.id
+				x, y = rect.Min.X, rect.Min.Y
+			}
+		}
 		win.id = w32.CreateWindowEx(uint(w32.WS_EX_CLIENTEDGE),
 			stringToUTF16(baseClass), stringToUTF16(""),
 			uint(w32.WS_OVERLAPPEDWINDOW),
-			150, 150, rect.Dx()+extraX, rect.Dy()+extraY,
-			0, 0, instance, nil)
+			x, y, rect.Dx()+extraX, rect.Dy()+extraY,
+			owner, 0, instance, nil)
 		if win.id == 0 {
 			log.Printf("w32: error: %v\n", getLastError())
 			os.Exit(1)
@@ -98,10 +133,19 @@ func (win *window) Close() {
 	vc := win.w.Property(sparta.Childs)
 	if vc != nil {
 		for _, c := range vc.([]sparta.Widget) {
-			c.Window().Close()
+			// a child can be closed before its parent
+			if cw := c.Window(); cw != nil {
+				cw.Close()
+			}
 		}
 	}
 	delete(widgetTable, win.id)
+	if focus == win {
+		focus = nil
+	}
+	if grab == win.w {
+		ungrab()
+	}
 
 	if win.w.Property(sparta.Parent) != nil {
 		win.w.SetProperty(sparta.Parent, nil)
@@ -129,6 +173,19 @@ func (win *window) SetProperty(p sparta.Property, v interface{}) {
 	case sparta.Geometry:
 		val := v.(image.Rectangle)
 		w32.MoveWindow(win.id, val.Min.X, val.Min.Y, val.Dx(), val.Dy(), true)
+	case sparta.Visible:
+		if v.(bool) {
+			w32.ShowWindow(win.id, w32.SW_SHOW)
+		} else {
+			w32.ShowWindow(win.id, w32.SW_HIDE)
+		}
+	case sparta.Enabled:
+		disabled := !v.(bool)
+		if win.disabled != disabled {
+			win.disabled = disabled
+			w32.EnableWindow(win.id, !disabled)
+			updateTree(win.w)
+		}
 	case sparta.Foreground:
 		val := v.(color.RGBA)
 		win.fore = getBrush(val)
@@ -145,5 +202,52 @@ func (win *window) Update() {
 
 // Focus set the focus on the window.
 func (win *window) Focus() {
+	focus = win
 	w32.SetFocus(win.id)
 }
+
+// Focused returns the widget with the input focus.
+func focused() sparta.Widget {
+	if focus == nil {
+		return nil
+	}
+	return focus.w
+}
+
+// RootPos returns the position of a widget in screen coordinates.
+func rootPos(w sparta.Widget) image.Point {
+	win := w.Window().(*window)
+	x, y := clientToScreen(win.id, 0, 0)
+	return image.Pt(x, y)
+}
+
+// IsEnabled returns true if the window of a widget, and the windows of
+// all of its ancestors, are enabled.
+func isEnabled(w sparta.Widget) bool {
+	for w != nil {
+		if win, ok := w.Window().(*window); ok && win.disabled {
+			return false
+		}
+		p := w.Property(sparta.Parent)
+		if p == nil {
+			break
+		}
+		w = p.(sparta.Widget)
+	}
+	return true
+}
+
+// UpdateTree updates the window of a widget, and the windows of its
+// children.
+func updateTree(w sparta.Widget) {
+	win, ok := w.Window().(*window)
+	if !ok {
+		return
+	}
+	win.Update()
+	if vc := w.Property(sparta.Childs); vc != nil {
+		for _, c := range vc.([]sparta.Widget) {
+			updateTree(c)
+		}
+	}
+}
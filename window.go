@@ -61,3 +61,30 @@ type Window interface {
 var NewWindow = func(w Widget) {
 	panic("undefined NewWindow in the backend")
 }
+
+// RootPos returns the position of the origin of a widget in screen
+// coordinates.
+var RootPos = func(w Widget) image.Point {
+	panic("undefined RootPos in the backend")
+}
+
+// Grab sends all the pointer (mouse) events to a widget, until Ungrab is
+// called. Events in the widget, or in any widget that has it as an
+// ancestor (using the Parent property), are processed as usual, any other
+// pointer event is sent to the grabbing widget, with the location
+// relative to it. It is used by popups to know when the user clicks
+// outside of them.
+var Grab = func(w Widget) {
+	panic("undefined Grab in the backend")
+}
+
+// Ungrab releases the pointer grabbed with Grab.
+var Ungrab = func() {
+	panic("undefined Ungrab in the backend")
+}
+
+// Focused returns the widget that has the input focus, that is, the last
+// widget in which the Focus method was called, or nil.
+var Focused = func() Widget {
+	panic("undefined Focused in the backend")
+}
@@ -22,10 +22,20 @@ func (win *window) Draw(mode bool) {
 		return
 	}
 	if mode {
-		xwin.ChangeGC(win.gc, xgb.GCForeground, []uint32{win.fore})
+		win.resetFore()
 	}
 }
 
+// ResetFore sets the fore color of the window as the drawing color. The
+// color is grayed if the window is disabled.
+func (win *window) resetFore() {
+	if !isEnabled(win.w) {
+		win.SetColor(sparta.Foreground, win.w.Property(sparta.Foreground).(color.RGBA))
+		return
+	}
+	xwin.ChangeGC(win.gc, xgb.GCForeground, []uint32{win.fore})
+}
+
 // Text draws text in the window.
 func (win *window) Text(pt image.Point, text string) {
 	if len(text) == 0 {
@@ -120,6 +130,9 @@ func (win *window) SetColor(p sparta.Property, c color.RGBA) {
 	if (p != sparta.Background) && (p != sparta.Foreground) {
 		return
 	}
+	if (p == sparta.Foreground) && !isEnabled(win.w) {
+		c = grayed(c)
+	}
 	s := xwin.DefaultScreen()
 	code := getColorCode(c)
 	px, ok := pixelMap[code]
@@ -135,3 +148,14 @@ func (win *window) SetColor(p sparta.Property, c color.RGBA) {
 		xwin.ChangeGC(win.gc, xgb.GCBackground, []uint32{px})
 	}
 }
+
+// Grayed returns the grayed version of a color, used to draw the
+// foreground of disabled windows.
+func grayed(c color.RGBA) color.RGBA {
+	return color.RGBA{
+		R: c.R/3 + 128,
+		G: c.G/3 + 128,
+		B: c.B/3 + 128,
+		A: c.A,
+	}
+}
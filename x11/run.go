@@ -20,6 +20,8 @@ func init() {
 	sparta.Run = run
 	sparta.Close = closeApp
 	sparta.SendEvent = sendEvent
+	sparta.Grab = grabPointer
+	sparta.Ungrab = ungrab
 }
 
 var endChan = make(chan struct{})
@@ -53,6 +55,64 @@ const allEventMask = xgb.EventMaskKeyPress | xgb.EventMaskKeyRelease |
 	xgb.EventMaskPointerMotion | xgb.EventMaskButtonMotion |
 	xgb.EventMaskExposure | xgb.EventMaskStructureNotify
 
+const pointerMask = xgb.EventMaskButtonPress | xgb.EventMaskButtonRelease |
+	xgb.EventMaskPointerMotion
+
+// grab is the widget that grabs the pointer.
+var grab sparta.Widget
+
+// GrabPointer grabs the pointer for a widget.
+func grabPointer(w sparta.Widget) {
+	win := w.Window().(*window)
+	grab = w
+	xwin.GrabPointer(true, win.id, uint16(pointerMask), xgb.GrabModeAsync, xgb.GrabModeAsync, 0, 0, xgb.TimeCurrentTime)
+}
+
+// Ungrab releases the pointer.
+func ungrab() {
+	if grab == nil {
+		return
+	}
+	grab = nil
+	xwin.UngrabPointer(xgb.TimeCurrentTime)
+}
+
+// InGrab returns true if the widget is the grabbing widget, or one of its
+// descendants.
+func inGrab(w sparta.Widget) bool {
+	for w != nil {
+		if w == grab {
+			return true
+		}
+		p := w.Property(sparta.Parent)
+		if p == nil {
+			break
+		}
+		w = p.(sparta.Widget)
+	}
+	return false
+}
+
+// Grabbed returns the widget that receives a pointer event produced in
+// a window, and the location of the event, relative to that widget.
+func grabbed(w sparta.Widget, id xgb.Id, x, y int16) (sparta.Widget, image.Point) {
+	if (grab == nil) || inGrab(w) {
+		return w, image.Pt(int(x), int(y))
+	}
+	gWin := grab.Window().(*window)
+	tr, err := xwin.TranslateCoordinates(id, gWin.id, x, y)
+	if err != nil {
+		return w, image.Pt(int(x), int(y))
+	}
+	return grab, image.Pt(int(tr.DstX), int(tr.DstY))
+}
+
+// IsBlocked returns true if the input of a widget is blocked by other
+// widget (for example, a modal dialog).
+func isBlocked(w sparta.Widget) bool {
+	return sparta.IsBlock() && !sparta.IsBlocker(w)
+}
+
 // Run runs the x11 event loop.
 func run() {
 	evChan := make(chan xgb.Event)
@@ -86,28 +146,36 @@ func xEvent(e xgb.Event) {
 	switch event := e.(type) {
 	case xgb.ButtonPressEvent:
 		w, ok := widgetTable[event.Event]
-		if !ok {
+		if !ok || !isEnabled(w) {
+			break
+		}
+		w, loc := grabbed(w, event.Event, event.EventX, event.EventY)
+		if isBlocked(w) {
 			break
 		}
 		ev := sparta.MouseEvent{
 			Button: getButton(event.Detail),
 			State:  sparta.StateKey(event.State),
-			Loc:    image.Pt(int(event.EventX), int(event.EventY)),
+			Loc:    loc,
 		}
-		w.OnEvent(ev)
 		w.Focus()
+		w.OnEvent(ev)
 	case xgb.ButtonReleaseEvent:
 		if (event.Detail == 4) || (event.Detail == 5) {
 			break
 		}
 		w, ok := widgetTable[event.Event]
-		if !ok {
+		if !ok || !isEnabled(w) {
+			break
+		}
+		w, loc := grabbed(w, event.Event, event.EventX, event.EventY)
+		if isBlocked(w) {
 			break
 		}
 		ev := sparta.MouseEvent{
 			Button: -getButton(event.Detail),
 			State:  sparta.StateKey(event.State),
-			Loc:    image.Pt(int(event.EventX), int(event.EventY)),
+			Loc:    loc,
 		}
 		w.OnEvent(ev)
 	case xgb.ClientMessageEvent:
@@ -126,7 +194,7 @@ func xEvent(e xgb.Event) {
 			w.OnEvent(sparta.CommandEvent{Source: sw, Value: val})
 			break
 		case wmProtocols:
-			if w.Property(sparta.Parent) != nil {
+			if (w.Property(sparta.Parent) != nil) || isBlocked(w) {
 				break
 			}
 			if event.Type != wmProtocols {
@@ -142,7 +210,7 @@ func xEvent(e xgb.Event) {
 		if !ok {
 			break
 		}
-		if w.Property(sparta.Parent) != nil {
+		if (w.Property(sparta.Parent) != nil) || isBlocked(w) {
 			break
 		}
 		w.OnEvent(sparta.CloseEvent{})
@@ -152,12 +220,14 @@ func xEvent(e xgb.Event) {
 			break
 		}
 		rect := w.Property(sparta.Geometry).(image.Rectangle)
-		if (rect.Dx() == int(event.Width)) && (rect.Dy() == int(event.Height)) {
+		ev := sparta.ConfigureEvent{image.Rect(int(event.X), int(event.Y), int(event.X)+int(event.Width), int(event.Y)+int(event.Height))}
+		if rect.Eq(ev.Rect) {
 			break
 		}
-		ev := sparta.ConfigureEvent{image.Rect(int(event.X), int(event.Y), int(event.X)+int(event.Width), int(event.Y)+int(event.Height))}
 		w.OnEvent(ev)
-		xwin.ClearArea(true, event.Window, 0, 0, event.Width, event.Height)
+		if (rect.Dx() != int(event.Width)) || (rect.Dy() != int(event.Height)) {
+			xwin.ClearArea(true, event.Window, 0, 0, event.Width, event.Height)
+		}
 	case xgb.ExposeEvent:
 		// only proccess the last expose event
 		if event.Count != 0 {
@@ -176,14 +246,14 @@ func xEvent(e xgb.Event) {
 			Height: event.Height,
 		}
 		xwin.PolyFillRectangle(win.id, win.gc, []xgb.Rectangle{r})
-		xwin.ChangeGC(win.gc, xgb.GCForeground, []uint32{win.fore})
+		win.resetFore()
 		win.isExpose = true
 		ev := sparta.ExposeEvent{image.Rect(int(event.X), int(event.Y), int(event.X+event.Width), int(event.Y+event.Height))}
 		w.OnEvent(ev)
 		win.isExpose = false
 	case xgb.KeyPressEvent:
 		w, ok := widgetTable[event.Event]
-		if !ok {
+		if !ok || !isEnabled(w) || isBlocked(w) {
 			break
 		}
 		ev := sparta.KeyEvent{
@@ -194,7 +264,7 @@ func xEvent(e xgb.Event) {
 		w.OnEvent(ev)
 	case xgb.KeyReleaseEvent:
 		w, ok := widgetTable[event.Event]
-		if !ok {
+		if !ok || !isEnabled(w) || isBlocked(w) {
 			break
 		}
 		ev := sparta.KeyEvent{
@@ -208,18 +278,25 @@ func xEvent(e xgb.Event) {
 		if (ev.Key - 1) == sparta.KeyControl {
 			ev.Key = sparta.KeyControl
 		}
+		if ev.Key == -keyISOLeftTab {
+			ev.Key = -sparta.KeyTab
+		}
 		w.OnEvent(ev)
 	case xgb.MappingNotifyEvent:
 		setKeyboard()
 	case xgb.MotionNotifyEvent:
 		w, ok := widgetTable[event.Event]
-		if !ok {
+		if !ok || !isEnabled(w) {
+			break
+		}
+		w, loc := grabbed(w, event.Event, event.EventX, event.EventY)
+		if isBlocked(w) {
 			break
 		}
 		ev := sparta.MouseEvent{
 			Button: getButton(event.Detail),
 			State:  sparta.StateKey(event.State),
-			Loc:    image.Pt(int(event.EventX), int(event.EventY)),
+			Loc:    loc,
 		}
 		w.OnEvent(ev)
 	}
@@ -241,6 +318,10 @@ func getButton(button byte) sparta.MouseButton {
 	return 0
 }
 
+// keyISOLeftTab is the keysym of Shift+Tab in most keyboard maps, it is
+// reported as KeyTab (with the shift state).
+const keyISOLeftTab = 0xfe20
+
 // GetKeyValue gets keyboard encoding as document at:
 // http://tronche.com/gui/x/xlib/input/keyboard-encoding.html
 func getKeyValue(key, state int) int {
@@ -259,6 +340,9 @@ func getKeyValue(key, state int) int {
 		if (keyVal - 1) == sparta.KeyControl {
 			keyVal = int(sparta.KeyControl)
 		}
+		if keyVal == keyISOLeftTab {
+			keyVal = int(sparta.KeyTab)
+		}
 		return keyVal
 	}
 	if ((state & xgb.ModMaskShift) == 0) && ((state & xgb.ModMaskLock) == 0) {
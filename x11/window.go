@@ -17,6 +17,9 @@ import (
 // widgetTable holds a list of widgets.
 var widgetTable = make(map[xgb.Id]sparta.Widget)
 
+// focus is the window with the input focus.
+var focus *window
+
 // Window holds the window information.
 type window struct {
 	id xgb.Id
@@ -26,10 +29,13 @@ type window struct {
 	gc         xgb.Id // graphic context
 	isExpose   bool   //true if the window is processing an expose event.
 	back, fore uint32
+	disabled   bool
 }
 
 func init() {
 	sparta.NewWindow = newWindow
+	sparta.RootPos = rootPos
+	sparta.Focused = focused
 }
 
 // NewWindow creates a new window and assigns it to a widget.
@@ -43,7 +49,11 @@ func newWindow(w sparta.Widget) {
 		back: s.WhitePixel,
 		fore: s.BlackPixel,
 	}
-	if p := w.Property(sparta.Parent); p != nil {
+	popup := false
+	if v, ok := w.Property(sparta.Popup).(bool); ok {
+		popup = v
+	}
+	if p := w.Property(sparta.Parent); (p != nil) && !popup {
 		pw := p.(sparta.Widget)
 		pWin := pw.Window().(*window)
 		win.back = pWin.back
@@ -57,11 +67,21 @@ func newWindow(w sparta.Widget) {
 	xwin.CreateWindow(0, win.id, pId,
 		int16(r.Min.X), int16(r.Min.Y), uint16(r.Dx()), uint16(r.Dy()), 0,
 		xgb.WindowClassInputOutput, s.RootVisual, 0, nil)
-	xwin.ChangeWindowAttributes(win.id, xgb.CWBackPixel|xgb.CWEventMask,
-		[]uint32{
-			win.back,
-			allEventMask,
-		})
+	if popup {
+		// popups are not managed by the window manager
+		xwin.ChangeWindowAttributes(win.id, xgb.CWBackPixel|xgb.CWOverrideRedirect|xgb.CWEventMask,
+			[]uint32{
+				win.back,
+				1,
+				allEventMask,
+			})
+	} else {
+		xwin.ChangeWindowAttributes(win.id, xgb.CWBackPixel|xgb.CWEventMask,
+			[]uint32{
+				win.back,
+				allEventMask,
+			})
+	}
 	font := xwin.NewId()
 	xwin.OpenFont(font, fixed)
 	xwin.CreateGC(win.gc, win.id, xgb.GCBackground|xgb.GCForeground|xgb.GCFont,
@@ -71,8 +91,17 @@ func newWindow(w sparta.Widget) {
 			uint32(font),
 		})
 	xwin.CloseFont(font)
+	if o, ok := w.Property(sparta.Owner).(sparta.Widget); ok && (o != nil) {
+		if oWin, ok := o.Window().(*window); ok {
+			data := make([]byte, 4)
+			put32(data, uint32(oWin.id))
+			xwin.ChangeProperty(xgb.PropModeReplace, win.id, xgb.AtomWmTransientFor, xgb.AtomWindow, 32, data)
+		}
+	}
 	xwin.MapWindow(win.id)
-	xwin.ChangeProperty(xgb.PropModeReplace, win.id, wmProtocols, atomType, 32, wmDelete)
+	if !popup {
+		xwin.ChangeProperty(xgb.PropModeReplace, win.id, wmProtocols, atomType, 32, wmDelete)
+	}
 }
 
 // Close closes the window.
@@ -81,10 +110,19 @@ func (win *window) Close() {
 	vc := win.w.Property(sparta.Childs)
 	if vc != nil {
 		for _, c := range vc.([]sparta.Widget) {
-			c.Window().Close()
+			// a child can be closed before its parent
+			if cw := c.Window(); cw != nil {
+				cw.Close()
+			}
 		}
 	}
 	delete(widgetTable, win.id)
+	if focus == win {
+		focus = nil
+	}
+	if grab == win.w {
+		ungrab()
+	}
 
 	if win.w.Property(sparta.Parent) != nil {
 		win.w.SetProperty(sparta.Parent, nil)
@@ -119,6 +157,18 @@ func (win *window) SetProperty(p sparta.Property, v interface{}) {
 				uint32(val.Dx()),
 				uint32(val.Dy()),
 			})
+	case sparta.Visible:
+		if v.(bool) {
+			xwin.MapWindow(win.id)
+		} else {
+			xwin.UnmapWindow(win.id)
+		}
+	case sparta.Enabled:
+		disabled := !v.(bool)
+		if win.disabled != disabled {
+			win.disabled = disabled
+			updateTree(win.w)
+		}
 	case sparta.Foreground:
 		val := v.(color.RGBA)
 		s := xwin.DefaultScreen()
@@ -154,5 +204,55 @@ func (win *window) Update() {
 
 // Focus set the focus on the window.
 func (win *window) Focus() {
+	focus = win
 	xwin.SetInputFocus(xgb.InputFocusNone, win.id, xgb.TimeCurrentTime)
 }
+
+// Focused returns the widget with the input focus.
+func focused() sparta.Widget {
+	if focus == nil {
+		return nil
+	}
+	return focus.w
+}
+
+// RootPos returns the position of a widget in screen coordinates.
+func rootPos(w sparta.Widget) image.Point {
+	win := w.Window().(*window)
+	tr, err := xwin.TranslateCoordinates(win.id, xwin.DefaultScreen().Root, 0, 0)
+	if err != nil {
+		return image.ZP
+	}
+	return image.Pt(int(tr.DstX), int(tr.DstY))
+}
+
+// IsEnabled returns true if the window of a widget, and the windows of
+// all of its ancestors, are enabled.
+func isEnabled(w sparta.Widget) bool {
+	for w != nil {
+		if win, ok := w.Window().(*window); ok && win.disabled {
+			return false
+		}
+		p := w.Property(sparta.Parent)
+		if p == nil {
+			break
+		}
+		w = p.(sparta.Widget)
+	}
+	return true
+}
+
+// UpdateTree updates the window of a widget, and the windows of its
+// children.
+func updateTree(w sparta.Widget) {
+	win, ok := w.Window().(*window)
+	if !ok {
+		return
+	}
+	win.Update()
+	if vc := w.Property(sparta.Childs); vc != nil {
+		for _, c := range vc.([]sparta.Widget) {
+			updateTree(c)
+		}
+	}
+}